@@ -0,0 +1,218 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// alerter routes watch changes by priority. Critical domains alert through
+// every notifier as soon as they change, and again every repeat until
+// acknowledged with `domainr ack`. Other changes are batched into a single
+// desktop notification (when notify is set) and Slack message (when slack
+// is) per round, or per digest period when one is. Every change is also
+// posted to the webhook, when set, as it happens.
+type alerter struct {
+	critical map[string]bool
+	repeat   time.Duration
+	digest   time.Duration
+	notify   bool
+	slack    string
+	webhook  string
+
+	mu sync.Mutex
+	// pending holds unacknowledged critical changes by domain, with when
+	// they changed.
+	pending map[string]time.Time
+	batch   []string
+}
+
+func newAlerter(critical []string, repeat, digest time.Duration, out outputConfig) *alerter {
+	a := &alerter{
+		critical: make(map[string]bool),
+		repeat:   repeat,
+		digest:   digest,
+		notify:   out.Notify,
+		slack:    out.Slack,
+		webhook:  out.Webhook,
+		pending:  make(map[string]time.Time),
+	}
+	for _, d := range critical {
+		a.critical[strings.ToLower(d)] = true
+	}
+	return a
+}
+
+// change records a domain becoming available, alerting right away if it is
+// critical.
+func (a *alerter) change(c statusChange) {
+	if a.webhook != "" {
+		notifyWebhook(a.webhook, webhookEvent{Event: "change", Change: &c})
+	}
+	key := strings.ToLower(c.Domain)
+	a.mu.Lock()
+	if !a.critical[key] {
+		a.batch = append(a.batch, c.Domain)
+		a.mu.Unlock()
+		return
+	}
+	a.pending[key] = c.ChangedAt
+	a.mu.Unlock()
+	a.alertCritical(c.Domain)
+}
+
+// roundDone flushes the round's changes unless they go into a digest.
+func (a *alerter) roundDone() {
+	if a.digest == 0 {
+		a.flush()
+	}
+}
+
+// flush sends the normal changes collected since the last flush as one
+// notification.
+func (a *alerter) flush() {
+	a.mu.Lock()
+	batch := a.batch
+	a.batch = nil
+	a.mu.Unlock()
+	if len(batch) == 0 {
+		return
+	}
+	if a.digest > 0 {
+		fmt.Fprintf(os.Stderr, "Digest: %d domain(s) became available: %s\n", len(batch), strings.Join(batch, ", "))
+	}
+	if a.notify {
+		notifyAvailable(batch)
+	}
+	if a.slack != "" {
+		lines := make([]string, len(batch))
+		for i, d := range batch {
+			lines[i] = ":large_green_circle: *" + slackEscape.Replace(d) + "* became available"
+		}
+		notifySlack(a.slack, strings.Join(lines, "\n"))
+	}
+}
+
+// run repeats critical alerts and sends digests until ctx is cancelled.
+func (a *alerter) run(ctx context.Context) {
+	go a.repeatUntilAcked(ctx)
+	if a.digest <= 0 {
+		return
+	}
+	ticker := time.NewTicker(a.digest)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			a.flush()
+		}
+	}
+}
+
+// repeatUntilAcked re-alerts unacknowledged critical changes every repeat
+// until ctx is cancelled.
+func (a *alerter) repeatUntilAcked(ctx context.Context) {
+	if len(a.critical) == 0 || a.repeat <= 0 {
+		return
+	}
+	ticker := time.NewTicker(a.repeat)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		acks := loadAcks()
+		var unacked []string
+		a.mu.Lock()
+		for key, changedAt := range a.pending {
+			if acked, ok := acks[key]; ok && !acked.Before(changedAt) {
+				delete(a.pending, key)
+				continue
+			}
+			unacked = append(unacked, key)
+		}
+		a.mu.Unlock()
+		for _, d := range unacked {
+			a.alertCritical(d)
+		}
+	}
+}
+
+// alertCritical fires every notifier for a critical domain: the terminal
+// bell, stderr, a desktop notification and Slack when configured.
+func (a *alerter) alertCritical(domain string) {
+	fmt.Fprintf(os.Stderr, "\a%s%sCRITICAL:%s %s is available (run `domainr ack %s` to stop repeating)\n",
+		colorRed, colorBold, colorReset, domain, domain)
+	if err := desktopNotify("Critical: "+domain+" is available", "Run `domainr ack "+domain+"` to acknowledge"); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+	}
+	if a.slack != "" {
+		notifySlack(a.slack, ":rotating_light: *Critical: "+slackEscape.Replace(domain)+" is available*\nRun `domainr ack "+slackEscape.Replace(domain)+"` to acknowledge")
+	}
+}
+
+func runAck(ctx context.Context, args []string) {
+	fs := flag.NewFlagSet("ack", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, `Usage: domainr ack <domain> [...]
+
+Acknowledge critical watch alerts so running watches stop repeating them.
+Later changes to the same domains alert again.
+
+Flags:
+`)
+		fs.PrintDefaults()
+	}
+	applyConfig(fs)
+	domains := parseArgs(fs, args)
+	if len(domains) == 0 {
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	acks := loadAcks()
+	for _, d := range domains {
+		acks[strings.ToLower(d)] = time.Now()
+	}
+	if err := saveAcks(acks); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Fprintf(os.Stderr, "Acknowledged %s\n", strings.Join(domains, ", "))
+}
+
+func acksPath() string {
+	return filepath.Join(dataDir(), "acks.json")
+}
+
+// loadAcks returns when each domain's alerts were last acknowledged.
+func loadAcks() map[string]time.Time {
+	acks := make(map[string]time.Time)
+	if data, err := os.ReadFile(acksPath()); err == nil {
+		json.Unmarshal(data, &acks)
+	}
+	return acks
+}
+
+func saveAcks(acks map[string]time.Time) error {
+	data, err := json.Marshal(acks)
+	if err != nil {
+		return err
+	}
+	path := acksPath()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"time"
+
+	"github.com/jpoz/domainr/pkg/domainr"
+)
+
+// remoteBrowserFlags defines the flags configuring a hosted browser on fs,
+// returning a function that reads them after parsing. API keys and the
+// Browserbase project fall back to the providers' usual environment
+// variables.
+func remoteBrowserFlags(fs *flag.FlagSet, keepAlive time.Duration) func() domainr.RemoteBrowser {
+	provider := fs.String("browser-provider", "", "Scrape in a hosted browser instead of launching Chromium: browserless or browserbase")
+	endpoint := fs.String("browser-endpoint", "", "WebSocket `URL` of a hosted browser to connect to over CDP (default: the provider's)")
+	apiKey := fs.String("browser-api-key", "", "API key for the browser provider (default: $BROWSERLESS_TOKEN or $BROWSERBASE_API_KEY)")
+	project := fs.String("browser-project", "", "Browserbase project `ID` (default: $BROWSERBASE_PROJECT_ID)")
+	region := fs.String("browser-region", "", "Region the provider runs the browser in, e.g. sfo or lon for browserless, us-west-2 for Browserbase")
+	keep := fs.Duration("browser-keep-alive", keepAlive, "How long the provider keeps a browser session running; Browserbase sessions with one also survive dropped connections")
+	return func() domainr.RemoteBrowser {
+		r := domainr.RemoteBrowser{
+			Provider:  *provider,
+			Endpoint:  *endpoint,
+			APIKey:    *apiKey,
+			ProjectID: *project,
+			Region:    *region,
+			KeepAlive: *keep,
+		}
+		switch r.Provider {
+		case domainr.ProviderBrowserless:
+			if r.APIKey == "" {
+				r.APIKey = os.Getenv("BROWSERLESS_TOKEN")
+			}
+		case domainr.ProviderBrowserbase:
+			if r.APIKey == "" {
+				r.APIKey = os.Getenv("BROWSERBASE_API_KEY")
+			}
+			if r.ProjectID == "" {
+				r.ProjectID = os.Getenv("BROWSERBASE_PROJECT_ID")
+			}
+		}
+		return r
+	}
+}
@@ -0,0 +1,216 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/jpoz/domainr/pkg/domainr"
+)
+
+const bruteChunkSize = 256
+
+// bruteProgress is persisted after every chunk so an interrupted run can
+// resume where it stopped.
+type bruteProgress struct {
+	Length  int    `json:"length"`
+	Charset string `json:"charset"`
+	TLD     string `json:"tld"`
+	Next    int64  `json:"next"`
+}
+
+func runBrute(ctx context.Context, args []string) {
+	fs := flag.NewFlagSet("brute", flag.ExitOnError)
+	length := fs.Int("length", 0, "Length of the names to enumerate")
+	charset := fs.String("charset", "abcdefghijklmnopqrstuvwxyz", "Characters to build names from")
+	tld := fs.String("tld", "com", "TLD to check")
+	source := fs.String("source", "dns", "Heuristic source to check with (only dns is supported)")
+	workers := fs.Int("workers", 16, "Number of concurrent lookups")
+	minScore := fs.Float64("min-score", 0, "Skip names scoring below this pronounceability (0-1, e.g. 0.1)")
+	resolver := fs.String("resolver", "", "DNS server for lookups: `host:port`, or an https:// DNS-over-HTTPS URL (default: system resolver)")
+	stateFile := fs.String("state", "", "Progress file (default: in the user cache directory)")
+	restart := fs.Bool("restart", false, "Ignore saved progress and start from the beginning")
+	jsonOut := fs.Bool("json", false, "Print results as JSON")
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: domainr brute -length N [flags]\n\nEnumerate every name of a given length and report the ones that look\nunregistered. Only heuristic sources are used, never the browser, and\nprogress is saved so an interrupted run resumes where it stopped.\n\nFlags:\n")
+		fs.PrintDefaults()
+	}
+	applyConfig(fs)
+	fs.Parse(args)
+	setResolver(*resolver)
+
+	if *length <= 0 || *charset == "" || *workers <= 0 {
+		fs.Usage()
+		os.Exit(1)
+	}
+	if *source != "dns" {
+		fmt.Fprintf(os.Stderr, "Error: unsupported source %q (brute force only uses heuristic sources: dns)\n", *source)
+		os.Exit(1)
+	}
+
+	chars := []rune(strings.ToLower(*charset))
+	total := math.Pow(float64(len(chars)), float64(*length))
+	if total > math.MaxInt64 {
+		fmt.Fprintln(os.Stderr, "Error: search space too large")
+		os.Exit(1)
+	}
+
+	progress := bruteProgress{Length: *length, Charset: string(chars), TLD: strings.TrimPrefix(*tld, ".")}
+	wildcard, err := domainr.HasWildcardDNS(ctx, progress.TLD)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: probing .%s for wildcard DNS: %v\n", progress.TLD, err)
+		os.Exit(1)
+	}
+	if wildcard {
+		fmt.Fprintf(os.Stderr, "Error: nonexistent .%s names resolve, so DNS can't tell which are unregistered (try another -resolver)\n", progress.TLD)
+		os.Exit(1)
+	}
+	path := *stateFile
+	if path == "" {
+		path = defaultBrutePath(progress)
+	}
+	if !*restart {
+		resumeBrute(path, &progress)
+	}
+	if progress.Next > 0 {
+		fmt.Fprintf(os.Stderr, "Resuming at %d of %d\n", progress.Next, int64(total))
+	}
+
+	sink, err := outputConfig{JSON: *jsonOut}.sink(os.Stdout, *length+len(progress.TLD)+1)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	defer sink.Close()
+
+	found, skipped := 0, 0
+	for progress.Next < int64(total) {
+		end := min(progress.Next+bruteChunkSize, int64(total))
+		results, filtered := bruteChunk(ctx, chars, progress, end, *workers, *minScore)
+		if ctx.Err() != nil {
+			// Lookups in this chunk were cut short; redo it on resume
+			fmt.Fprintf(os.Stderr, "Interrupted, progress saved to %s\n", path)
+			sink.Close()
+			os.Exit(130)
+		}
+		skipped += filtered
+		for _, r := range results {
+			if r.Status == domainr.StatusAvailable {
+				found++
+				sink.Write(r)
+			}
+		}
+
+		progress.Next = end
+		if err := saveBrute(path, progress); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: saving progress: %v\n", err)
+		}
+	}
+	if skipped > 0 {
+		fmt.Fprintf(os.Stderr, "Skipped %d hard-to-pronounce names\n", skipped)
+	}
+	fmt.Fprintf(os.Stderr, "Checked %d names, %d look unregistered\n", int64(total)-int64(skipped), found)
+}
+
+// bruteChunk looks up candidates [progress.Next, end) concurrently and returns
+// their results in enumeration order. Names that aren't valid labels are
+// omitted, as are names scoring below minScore, which are counted.
+func bruteChunk(ctx context.Context, chars []rune, progress bruteProgress, end int64, workers int, minScore float64) ([]domainr.DomainResult, int) {
+	results := make([]domainr.DomainResult, end-progress.Next)
+	var filtered atomic.Int64
+	indexes := make(chan int64)
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				label := bruteName(chars, progress.Length, i)
+				if strings.HasPrefix(label, "-") || strings.HasSuffix(label, "-") {
+					continue
+				}
+				if minScore > 0 && pronounceability(label) < minScore {
+					filtered.Add(1)
+					continue
+				}
+				domain := label + "." + progress.TLD
+				r := domainr.DomainResult{Domain: domain, CheckedAt: time.Now(), Source: domainr.SourceDNS}
+				status, err := domainr.DNSStatus(ctx, domain)
+				r.Status = status
+				if err != nil {
+					r.Reason = err.Error()
+				} else if status == domainr.StatusAvailable {
+					r.Reason = "no DNS delegation (unverified)"
+				}
+				results[i-progress.Next] = r
+			}
+		}()
+	}
+	for i := progress.Next; i < end; i++ {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+	return results, int(filtered.Load())
+}
+
+// bruteName returns the i-th name of the given length in lexicographic order
+// over chars.
+func bruteName(chars []rune, length int, i int64) string {
+	name := make([]rune, length)
+	base := int64(len(chars))
+	for pos := length - 1; pos >= 0; pos-- {
+		name[pos] = chars[i%base]
+		i /= base
+	}
+	return string(name)
+}
+
+func defaultBrutePath(p bruteProgress) string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		dir = os.TempDir()
+	}
+	name := fmt.Sprintf("brute-%d-%s-%x.json", p.Length, p.TLD, p.Charset)
+	if len(name) > 100 {
+		name = fmt.Sprintf("brute-%d-%s.json", p.Length, p.TLD)
+	}
+	return filepath.Join(dir, "domainr", name)
+}
+
+// resumeBrute loads saved progress into p if it was made with the same
+// parameters.
+func resumeBrute(path string, p *bruteProgress) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return
+	}
+	var saved bruteProgress
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return
+	}
+	if saved.Length != p.Length || saved.Charset != p.Charset || saved.TLD != p.TLD {
+		fmt.Fprintf(os.Stderr, "Saved progress in %s is for a different search, starting over\n", path)
+		return
+	}
+	p.Next = saved.Next
+}
+
+func saveBrute(path string, p bruteProgress) error {
+	data, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/jpoz/domainr/pkg/domainr"
+)
+
+// budgetFlag holds maximum prices by TLD, set with -budget io=40,com=15 and
+// repeatable. A TLD of "*" applies to TLDs without their own budget.
+type budgetFlag map[string]float64
+
+func (b budgetFlag) String() string {
+	var parts []string
+	for tld, limit := range b {
+		parts = append(parts, fmt.Sprintf("%s=%g", tld, limit))
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, ",")
+}
+
+func (b budgetFlag) Set(s string) error {
+	for _, item := range splitList(s) {
+		tld, amount, ok := strings.Cut(item, "=")
+		if !ok {
+			// A bare amount is a default for every TLD
+			tld, amount = "*", item
+		}
+		limit, err := strconv.ParseFloat(strings.TrimSpace(amount), 64)
+		if err != nil || limit < 0 {
+			return fmt.Errorf("invalid budget %q: expected tld=amount", item)
+		}
+		b[strings.TrimPrefix(strings.ToLower(strings.TrimSpace(tld)), ".")] = limit
+	}
+	return nil
+}
+
+// limit returns the budget for domain's TLD, preferring the longest matching
+// suffix.
+func (b budgetFlag) limit(domain string) (float64, bool) {
+	name := strings.ToLower(domain)
+	for {
+		dot := strings.IndexByte(name, '.')
+		if dot < 0 {
+			break
+		}
+		name = name[dot+1:]
+		if limit, ok := b[name]; ok {
+			return limit, true
+		}
+	}
+	limit, ok := b["*"]
+	return limit, ok
+}
+
+// overBudget reports whether r is available only at a price above its TLD's
+// budget. Results without a known price are never over budget.
+func (b budgetFlag) overBudget(r domainr.DomainResult) bool {
+	if r.Status != domainr.StatusAvailable && r.Status != domainr.StatusPremium {
+		return false
+	}
+	limit, ok := b.limit(r.Domain)
+	if !ok {
+		return false
+	}
+	price, ok := domainr.ParsePrice(r.Price)
+	return ok && price > limit
+}
+
+// budgetSink drops results priced over budget, or with flag set marks them,
+// reporting how many on close.
+type budgetSink struct {
+	resultSink
+	budget budgetFlag
+	flag   bool
+	// hide, if set, is told about dropped results, so the live sink can
+	// clear their pending rows
+	hide    func(domainr.DomainResult)
+	dropped int
+}
+
+func (s *budgetSink) Write(r domainr.DomainResult) error {
+	if s.budget.overBudget(r) {
+		s.dropped++
+		if !s.flag {
+			if s.hide != nil {
+				s.hide(r)
+			}
+			return nil
+		}
+		r.OverBudget = true
+	}
+	return s.resultSink.Write(r)
+}
+
+func (s *budgetSink) Close() error {
+	err := s.resultSink.Close()
+	if s.dropped > 0 && !s.flag {
+		fmt.Fprintf(os.Stderr, "Hid %d result(s) priced over budget\n", s.dropped)
+	}
+	return err
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"maps"
+	"testing"
+)
+
+func TestBudgetFlagSet(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    budgetFlag
+		wantErr bool
+	}{
+		{in: "io=40,com=15", want: budgetFlag{"io": 40, "com": 15}},
+		{in: " .IO = 40.5 ", want: budgetFlag{"io": 40.5}},
+		{in: "25", want: budgetFlag{"*": 25}},
+		{in: "co.uk=10,*=30", want: budgetFlag{"co.uk": 10, "*": 30}},
+		{in: "io=40,,", want: budgetFlag{"io": 40}},
+		{in: "io=0", want: budgetFlag{"io": 0}},
+		{in: "io=cheap", wantErr: true},
+		{in: "io=-5", wantErr: true},
+		{in: "io=", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got := budgetFlag{}
+			err := got.Set(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Set() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !maps.Equal(got, tt.want) {
+				t.Errorf("Set() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
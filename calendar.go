@@ -0,0 +1,167 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jpoz/domainr/pkg/domainr"
+)
+
+func runCalendar(ctx context.Context, args []string) {
+	fs := flag.NewFlagSet("calendar", flag.ExitOnError)
+	listFile := fs.String("f", "", "Read domains from a file, one or more per line")
+	output := fs.String("o", "", "Write the calendar to `file` instead of stdout")
+	remind := fs.String("remind", "30,7,1", "Comma-separated days before each expiry to raise reminders")
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, `Usage: domainr calendar [flags] <domain|-> [...]
+
+Look up the expiration dates of registered domains over RDAP (falling back
+to WHOIS) and write them as an iCalendar (.ics) file, one all-day event per
+domain with reminders ahead of it, so upcoming expiries and possible drops
+show up in your calendar. Point it at a watchlist with -f and re-run it from
+cron to keep a subscribed calendar current.
+
+Flags:
+`)
+		fs.PrintDefaults()
+	}
+	applyConfig(fs)
+	args = parseArgs(fs, args)
+	if len(args) == 0 && *listFile == "" {
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	var reminders []int
+	for _, s := range splitList(*remind) {
+		days, err := strconv.Atoi(s)
+		if err != nil || days < 0 {
+			fmt.Fprintf(os.Stderr, "Error: -remind: invalid number of days %q\n", s)
+			os.Exit(1)
+		}
+		reminders = append(reminders, days)
+	}
+
+	domains, err := collectDomains(args, *listFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	domains = prepareDomains(domains)
+
+	var events []expiryEvent
+	for _, d := range domains {
+		reg, err := domainr.LookupRegistration(ctx, d)
+		if ctx.Err() != nil {
+			fmt.Fprintln(os.Stderr, "Interrupted")
+			os.Exit(130)
+		}
+		switch {
+		case err != nil:
+			fmt.Fprintf(os.Stderr, "Skipping %s: %v\n", d, err)
+		case reg.Expires == nil:
+			fmt.Fprintf(os.Stderr, "Skipping %s: no expiration date published\n", d)
+		case reg.Expires.Before(time.Now()):
+			fmt.Fprintf(os.Stderr, "Skipping %s: already expired on %s\n", d, reg.Expires.Format(time.DateOnly))
+		default:
+			events = append(events, expiryEvent{Domain: d, Registration: reg})
+		}
+	}
+
+	w := io.Writer(os.Stdout)
+	if *output != "" {
+		f, err := os.Create(*output)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		defer f.Close()
+		w = f
+	}
+	if err := writeICS(w, events, reminders, time.Now()); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Fprintf(os.Stderr, "Wrote %d expiry event(s)\n", len(events))
+}
+
+// expiryEvent is a domain's upcoming expiration.
+type expiryEvent struct {
+	Domain       string
+	Registration domainr.Registration
+}
+
+// icsEscape escapes text property values (RFC 5545 section 3.3.11).
+var icsEscape = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`)
+
+// writeICS writes events as an iCalendar file with a display alarm the given
+// numbers of days before each.
+func writeICS(w io.Writer, events []expiryEvent, reminders []int, now time.Time) error {
+	var lines []string
+	add := func(format string, args ...any) { lines = append(lines, fmt.Sprintf(format, args...)) }
+
+	add("BEGIN:VCALENDAR")
+	add("VERSION:2.0")
+	add("PRODID:-//domainr//Domain expiries//EN")
+	add("CALSCALE:GREGORIAN")
+	add("X-WR-CALNAME:Domain expiries")
+	for _, e := range events {
+		expires := e.Registration.Expires.UTC()
+		description := "Expires " + expires.Format(time.DateOnly) + ".\n"
+		if e.Registration.Registrar != "" {
+			description += "Registrar: " + e.Registration.Registrar + "\n"
+		}
+		description += "Unless renewed, most gTLD names drop back to availability 30 to 80 days later, after the grace and redemption periods.\n" + domainr.PurchaseURL(e.Domain)
+
+		add("BEGIN:VEVENT")
+		add("UID:%s-expiry@domainr", strings.ToLower(domainr.ToASCII(e.Domain)))
+		add("DTSTAMP:%s", now.UTC().Format("20060102T150405Z"))
+		add("DTSTART;VALUE=DATE:%s", expires.Format("20060102"))
+		add("DTEND;VALUE=DATE:%s", expires.AddDate(0, 0, 1).Format("20060102"))
+		add("SUMMARY:%s", icsEscape.Replace(e.Domain+" expires"))
+		add("DESCRIPTION:%s", icsEscape.Replace(description))
+		add("TRANSP:TRANSPARENT")
+		for _, days := range reminders {
+			add("BEGIN:VALARM")
+			add("ACTION:DISPLAY")
+			add("DESCRIPTION:%s", icsEscape.Replace(fmt.Sprintf("%s expires in %d day(s)", e.Domain, days)))
+			add("TRIGGER:-P%dD", days)
+			add("END:VALARM")
+		}
+		add("END:VEVENT")
+	}
+	add("END:VCALENDAR")
+
+	for _, line := range lines {
+		if _, err := io.WriteString(w, foldICSLine(line)+"\r\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// foldICSLine splits lines longer than 75 octets into continuation lines,
+// without breaking UTF-8 sequences.
+func foldICSLine(line string) string {
+	var b strings.Builder
+	width := 75
+	for len(line) > width {
+		cut := width
+		for cut > 0 && line[cut]&0xC0 == 0x80 {
+			cut--
+		}
+		b.WriteString(line[:cut])
+		b.WriteString("\r\n ")
+		line = line[cut:]
+		// Continuation lines start with a space, which counts
+		width = 74
+	}
+	b.WriteString(line)
+	return b.String()
+}
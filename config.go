@@ -0,0 +1,232 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// configPath returns where the config file lives: $DOMAINR_CONFIG when set,
+// otherwise domainr/config.toml under $XDG_CONFIG_HOME or ~/.config.
+func configPath() string {
+	if path := os.Getenv("DOMAINR_CONFIG"); path != "" {
+		return path
+	}
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		dir = filepath.Join(home, ".config")
+	}
+	return filepath.Join(dir, "domainr", "config.toml")
+}
+
+// configProfile is the config profile in use, from -profile or
+// $DOMAINR_PROFILE; see extractProfile.
+var configProfile = os.Getenv("DOMAINR_PROFILE")
+
+// extractProfile removes a -profile flag from args, which every command
+// accepts, setting configProfile from it.
+func extractProfile(args []string) []string {
+	rest := make([]string, 0, len(args))
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			return append(rest, args[i:]...)
+		}
+		name, value, hasValue := strings.Cut(strings.TrimPrefix(strings.TrimPrefix(arg, "-"), "-"), "=")
+		if !strings.HasPrefix(arg, "-") || name != "profile" {
+			rest = append(rest, arg)
+			continue
+		}
+		if !hasValue && i+1 < len(args) {
+			i++
+			value = args[i]
+		}
+		configProfile = value
+	}
+	return rest
+}
+
+// dataDir is where results, backoff state and acknowledgements are kept:
+// domainr under the user cache directory, with a subdirectory for each
+// profile.
+func dataDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		dir = os.TempDir()
+	}
+	dir = filepath.Join(dir, "domainr")
+	if configProfile != "" {
+		dir = filepath.Join(dir, "profiles", configProfile)
+	}
+	return dir
+}
+
+// loadConfig reads the config file, returning nil if there is none. Its
+// top-level keys are flag names and values, applying to every command with
+// such a flag; a table named after a command applies to that command only.
+// The env table holds environment variables, such as API keys, used when
+// not already set. Tables under profiles hold the same settings for one
+// profile each, overriding the rest when it is in use.
+func loadConfig() (map[string]any, error) {
+	path := configPath()
+	if path == "" {
+		return nil, nil
+	}
+	var cfg map[string]any
+	if _, err := toml.DecodeFile(path, &cfg); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// applyConfig sets the defaults of flagSet's flags from the config file, so
+// flags given on the command line still override them. It exits on an
+// unreadable file, an unknown profile or a value its flag rejects.
+func applyConfig(flagSet *flag.FlagSet) {
+	command := flagSet.Name()
+	if flagSet == flag.CommandLine {
+		command = ""
+	}
+	cfg, err := loadConfig()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	layers := []map[string]any{cfg}
+	if configProfile != "" {
+		profiles, _ := cfg["profiles"].(map[string]any)
+		profile, ok := profiles[configProfile].(map[string]any)
+		if !ok {
+			fmt.Fprintf(os.Stderr, "Error: no profile %q in %s\n", configProfile, configPath())
+			os.Exit(1)
+		}
+		layers = append(layers, profile)
+	}
+
+	// Later layers win; for env that means setting theirs first
+	for i := len(layers) - 1; i >= 0; i-- {
+		env, _ := layers[i]["env"].(map[string]any)
+		for key, value := range env {
+			if os.Getenv(key) == "" {
+				os.Setenv(key, configValue(value))
+			}
+		}
+	}
+	settings := make(map[string]any)
+	var unknown []string
+	for i, layer := range layers {
+		for key, value := range layer {
+			table, isTable := value.(map[string]any)
+			switch {
+			case !isTable:
+				settings[key] = value
+			case flagSet.Lookup(key) != nil:
+				settings[key] = flagTable(table)
+			}
+		}
+		prefix := ""
+		if i > 0 {
+			prefix = "profiles." + configProfile + "."
+		}
+		// Top-level keys may belong to other commands, which only the main
+		// command knows all of
+		if command == "" {
+			unknown = append(unknown, unknownKeys(layer, flagSet, prefix)...)
+		}
+		if section, ok := layer[command].(map[string]any); ok && command != "" {
+			for key, value := range section {
+				if flagSet.Lookup(key) == nil {
+					unknown = append(unknown, prefix+command+"."+key)
+					continue
+				}
+				if table, ok := value.(map[string]any); ok {
+					value = flagTable(table)
+				}
+				settings[key] = value
+			}
+		}
+	}
+	slices.Sort(unknown)
+	for _, key := range unknown {
+		fmt.Fprintf(os.Stderr, "Warning: %s: unknown setting %q\n", configPath(), key)
+	}
+
+	for key, value := range settings {
+		if flagSet.Lookup(key) == nil {
+			continue
+		}
+		if err := flagSet.Set(key, configValue(value)); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s: invalid value %q for %s: %v\n", configPath(), configValue(value), key, err)
+			os.Exit(1)
+		}
+	}
+}
+
+// commandNames are the commands main dispatches to, whose tables in the
+// config file apply to them alone.
+var commandNames = []string{
+	"ack", "brute", "calendar", "daemon", "from-project", "guard", "history", "init",
+	"keys", "localize", "monitor", "report", "rpc", "stealth-test", "suggest", "trends",
+}
+
+// commandFlags are the flags only some command other than the main one
+// has, which are fine as top-level keys.
+var commandFlags = []string{
+	"all", "cert-warn", "charset", "email", "file", "force", "health", "js-heap",
+	"key", "langs", "length", "max", "max-renderers", "min-score", "n", "note",
+	"o", "remind", "restart", "restart-memory", "schedule", "sig", "since",
+	"socket", "source", "state", "tld", "workers",
+}
+
+// unknownKeys returns the keys of a config layer that are no command's flag
+// or table, such as misspellings, given the main command's flags.
+func unknownKeys(layer map[string]any, main *flag.FlagSet, prefix string) []string {
+	var unknown []string
+	for key := range layer {
+		if key == "env" || key == "profiles" || main.Lookup(key) != nil ||
+			slices.Contains(commandNames, key) || slices.Contains(commandFlags, key) {
+			continue
+		}
+		unknown = append(unknown, prefix+key)
+	}
+	return unknown
+}
+
+// flagTable flattens a table given for a flag that takes key=value pairs,
+// such as [budget] with io = 40, into the flag's "io=40" form.
+func flagTable(table map[string]any) string {
+	pairs := make([]string, 0, len(table))
+	for key, value := range table {
+		pairs = append(pairs, key+"="+configValue(value))
+	}
+	slices.Sort(pairs)
+	return strings.Join(pairs, ",")
+}
+
+// configValue formats a TOML value as a flag would be given it. Arrays
+// become comma-separated lists.
+func configValue(value any) string {
+	if list, ok := value.([]any); ok {
+		items := make([]string, len(list))
+		for i, item := range list {
+			items[i] = fmt.Sprint(item)
+		}
+		return strings.Join(items, ",")
+	}
+	return fmt.Sprint(value)
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestFlagTable(t *testing.T) {
+	tests := []struct {
+		table map[string]any
+		want  string
+	}{
+		{map[string]any{"io": int64(40), "com": 15.5}, "com=15.5,io=40"},
+		{map[string]any{"*": int64(10)}, "*=10"},
+		{map[string]any{}, ""},
+	}
+	for _, tt := range tests {
+		got := flagTable(tt.table)
+		if got != tt.want {
+			t.Errorf("flagTable(%v) = %q, want %q", tt.table, got, tt.want)
+		}
+		b := budgetFlag{}
+		if err := b.Set(got); err != nil {
+			t.Errorf("budgetFlag.Set(%q): %v", got, err)
+		}
+	}
+}
+
+// TestCommandNames keeps commandNames in step with the commands in the
+// usage text, so config tables for new commands aren't reported unknown.
+func TestCommandNames(t *testing.T) {
+	_, commands, _ := strings.Cut(usageText, "Commands:\n")
+	commands, _, _ = strings.Cut(commands, "\n\n")
+	var listed []string
+	for _, line := range strings.Split(commands, "\n") {
+		if fields := strings.Fields(line); len(fields) > 0 {
+			listed = append(listed, fields[0])
+		}
+	}
+	if !slices.Equal(listed, commandNames) {
+		t.Errorf("commands in usage text = %v, commandNames = %v", listed, commandNames)
+	}
+}
+
+// TestCommandFlags keeps commandFlags in step with the flags the commands
+// define. The commands build their flag sets as they run, so the flags are
+// found in the source: those defined on a command's fs, less the main
+// command's, which it defines on flag.CommandLine directly or through
+// helpers such as remoteBrowserFlags.
+func TestCommandFlags(t *testing.T) {
+	files, err := filepath.Glob("*.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// defined holds each function's flags by flag set: "flag" for the main
+	// command's, "fs" for a command's or a helper's
+	defined := map[string]map[string][]string{}
+	var mainHelpers []string
+	fset := token.NewFileSet()
+	for _, file := range files {
+		if strings.HasSuffix(file, "_test.go") {
+			continue
+		}
+		f, err := parser.ParseFile(fset, file, nil, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, decl := range f.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Body == nil {
+				continue
+			}
+			flags := map[string][]string{}
+			defined[fn.Name.Name] = flags
+			ast.Inspect(fn.Body, func(n ast.Node) bool {
+				call, ok := n.(*ast.CallExpr)
+				if !ok {
+					return true
+				}
+				for _, arg := range call.Args {
+					if isSelector(arg, "flag", "CommandLine") {
+						if callee, ok := call.Fun.(*ast.Ident); ok {
+							mainHelpers = append(mainHelpers, callee.Name)
+						}
+					}
+				}
+				sel, ok := call.Fun.(*ast.SelectorExpr)
+				if !ok {
+					return true
+				}
+				recv, ok := sel.X.(*ast.Ident)
+				if !ok || (recv.Name != "flag" && recv.Name != "fs") {
+					return true
+				}
+				// The name comes after the destination in the *Var forms
+				arg := 0
+				if strings.HasSuffix(sel.Sel.Name, "Var") {
+					arg = 1
+				}
+				if len(call.Args) <= arg {
+					return true
+				}
+				if lit, ok := call.Args[arg].(*ast.BasicLit); ok && lit.Kind == token.STRING {
+					name, _ := strconv.Unquote(lit.Value)
+					flags[recv.Name] = append(flags[recv.Name], name)
+				}
+				return true
+			})
+		}
+	}
+
+	mainFlags, subFlags := map[string]bool{}, map[string]bool{}
+	for fn, flags := range defined {
+		for _, name := range flags["flag"] {
+			mainFlags[name] = true
+		}
+		for _, name := range flags["fs"] {
+			subFlags[name] = true
+			if slices.Contains(mainHelpers, fn) {
+				mainFlags[name] = true
+			}
+		}
+	}
+	var want []string
+	for name := range subFlags {
+		if !mainFlags[name] {
+			want = append(want, name)
+		}
+	}
+	slices.Sort(want)
+	if !slices.Equal(want, commandFlags) {
+		t.Errorf("flags only commands other than the main one have = %v, commandFlags = %v", want, commandFlags)
+	}
+}
+
+func isSelector(expr ast.Expr, pkg, name string) bool {
+	sel, ok := expr.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+	x, ok := sel.X.(*ast.Ident)
+	return ok && x.Name == pkg && sel.Sel.Name == name
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jpoz/domainr/pkg/domainr"
+)
+
+// guardEntry is a domain and the nameservers it is expected to delegate to.
+type guardEntry struct {
+	Domain      string
+	Nameservers []string
+}
+
+func runGuard(ctx context.Context, args []string) {
+	fs := flag.NewFlagSet("guard", flag.ExitOnError)
+	file := fs.String("f", "domainr-guard.txt", "File listing domains and their expected nameservers")
+	health := fs.Bool("health", false, "Also check DNSSEC, lame delegations and HTTPS certificate expiry")
+	resolver := fs.String("resolver", "", "DNS server for lookups: `host:port`, or an https:// DNS-over-HTTPS URL (default: system resolver)")
+	certWarn := fs.Duration("cert-warn", 14*24*time.Hour, "With -health, fail certificates expiring within this long")
+	noColor := fs.Bool("no-color", false, "Disable colored output (also disabled by NO_COLOR or when stdout is not a terminal)")
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, `Usage: domainr guard [-f file] [-health]
+
+Verify that a project's domains are still registered and delegated to the
+expected nameservers. Exits non-zero if any domain fails, for use in release
+pipelines and git hooks.
+
+Each line of the file is a domain followed by its nameservers:
+
+  example.com  ns1.example.net ns2.example.net
+
+With -health, each domain's DNSSEC signatures are validated against the DS
+records in its parent zone, every nameserver must answer authoritatively, and
+the HTTPS certificate must be valid and not close to expiry.
+
+Flags:
+`)
+		fs.PrintDefaults()
+	}
+	applyConfig(fs)
+	fs.Parse(args)
+	setResolver(*resolver)
+	setColor(*noColor)
+
+	entries, err := readGuardFile(*file)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	failed := 0
+	for _, e := range entries {
+		err := checkGuardEntry(ctx, e)
+		if err == nil && *health {
+			err = checkHealth(ctx, e.Domain, *certWarn)
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				os.Exit(130)
+			}
+			failed++
+			fmt.Printf("  %s%s%s  %s%sFAIL%s  %s\n", colorBold, e.Domain, colorReset, colorRed, colorBold, colorReset, strings.ReplaceAll(err.Error(), "\n", "; "))
+			continue
+		}
+		fmt.Printf("  %s%s%s  %s%sOK%s\n", colorBold, e.Domain, colorReset, colorGreen, colorBold, colorReset)
+	}
+
+	if failed > 0 {
+		fmt.Fprintf(os.Stderr, "%d of %d domain(s) failed the guard check\n", failed, len(entries))
+		os.Exit(1)
+	}
+}
+
+func checkGuardEntry(ctx context.Context, e guardEntry) error {
+	actual, err := domainr.LookupNameservers(ctx, e.Domain)
+	if errors.Is(err, domainr.ErrNoSuchDomain) {
+		return errors.New("not registered (expired?)")
+	}
+	if err != nil {
+		return fmt.Errorf("looking up nameservers: %w", err)
+	}
+	if !slices.Equal(actual, e.Nameservers) {
+		return fmt.Errorf("nameservers changed: expected %s, got %s",
+			strings.Join(e.Nameservers, " "), strings.Join(actual, " "))
+	}
+	return nil
+}
+
+// checkHealth reports DNSSEC, delegation and certificate problems for domain.
+// Unsigned domains are not a failure; broken signatures are.
+func checkHealth(ctx context.Context, domain string, certWarn time.Duration) error {
+	var problems []error
+
+	lame, err := domainr.FindLameDelegations(ctx, domain)
+	if err != nil {
+		problems = append(problems, fmt.Errorf("checking delegation: %w", err))
+	}
+	for _, ns := range lame {
+		problems = append(problems, fmt.Errorf("lame delegation: %s %s", ns.Host, ns.Reason))
+	}
+
+	if err := domainr.CheckDNSSEC(ctx, domain); err != nil && !errors.Is(err, domainr.ErrUnsigned) {
+		problems = append(problems, fmt.Errorf("DNSSEC: %w", err))
+	}
+
+	expires, err := domainr.CertExpiry(ctx, domain)
+	switch left := time.Until(expires); {
+	case err != nil:
+		problems = append(problems, fmt.Errorf("certificate: %w", err))
+	case left <= 0:
+		problems = append(problems, fmt.Errorf("certificate expired %s", expires.Format(time.DateOnly)))
+	case left < certWarn:
+		problems = append(problems, fmt.Errorf("certificate expires %s", expires.Format(time.DateOnly)))
+	}
+
+	return errors.Join(problems...)
+}
+
+func readGuardFile(name string) ([]guardEntry, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var entries []guardEntry
+	scanner := bufio.NewScanner(f)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) < 2 {
+			return nil, fmt.Errorf("%s:%d: expected a domain followed by nameservers", name, lineNo)
+		}
+
+		e := guardEntry{Domain: strings.ToLower(fields[0])}
+		for _, ns := range fields[1:] {
+			e.Nameservers = append(e.Nameservers, domainr.NormalizeHost(ns))
+		}
+		sort.Strings(e.Nameservers)
+		entries = append(entries, e)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("%s: no domains listed", name)
+	}
+	return entries, nil
+}
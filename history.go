@@ -0,0 +1,220 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jpoz/domainr/pkg/domainr"
+	_ "modernc.org/sqlite"
+)
+
+// historyDisabled stops checks from being recorded in the history database.
+var historyDisabled bool
+
+const historySchema = `
+CREATE TABLE IF NOT EXISTS checks (
+	id            INTEGER PRIMARY KEY,
+	domain        TEXT NOT NULL,
+	status        TEXT NOT NULL,
+	price         TEXT NOT NULL DEFAULT '',
+	renewal_price TEXT NOT NULL DEFAULT '',
+	reason        TEXT NOT NULL DEFAULT '',
+	source        TEXT NOT NULL DEFAULT '',
+	checked_at    TIMESTAMP NOT NULL
+);
+CREATE INDEX IF NOT EXISTS checks_domain ON checks (domain, checked_at);
+`
+
+var (
+	historyOnce sync.Once
+	historyDB   *sql.DB
+)
+
+// openHistory opens the history database in the data directory, creating
+// it on first use. It returns nil, after warning once, if the database
+// can't be opened, so checks carry on without it.
+func openHistory() *sql.DB {
+	historyOnce.Do(func() {
+		path := filepath.Join(dataDir(), "history.db")
+		db, err := openHistoryAt(path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: not recording history: %v\n", err)
+			return
+		}
+		historyDB = db
+	})
+	return historyDB
+}
+
+func openHistoryAt(path string) (*sql.DB, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+	db, err := sql.Open("sqlite", "file:"+path+"?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := db.Exec(historySchema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return db, nil
+}
+
+// recordHistory wraps emit to record each freshly checked result. Cached,
+// related and unknown results are skipped: they add no information about
+// the domain's state. Failures are warned about on log.
+func recordHistory(emit func(domainr.DomainResult) error, log io.Writer) func(domainr.DomainResult) error {
+	if historyDisabled {
+		return emit
+	}
+	db := openHistory()
+	if db == nil {
+		return emit
+	}
+	return func(r domainr.DomainResult) error {
+		if !r.Cached && !r.Related && r.Status != domainr.StatusUnknown {
+			_, err := db.Exec(`INSERT INTO checks (domain, status, price, renewal_price, reason, source, checked_at) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+				historyKey(r.Domain), r.Status.String(), r.Price, r.RenewalPrice, r.Reason, r.Source, r.CheckedAt.UTC())
+			if err != nil {
+				fmt.Fprintf(log, "Warning: recording history for %s: %v\n", r.Domain, err)
+			}
+		}
+		return emit(r)
+	}
+}
+
+func historyKey(domain string) string {
+	return strings.ToLower(domainr.ToASCII(domain))
+}
+
+// historyEntry is a stretch of consecutive checks that saw the same status
+// and price.
+type historyEntry struct {
+	Status       domainr.DomainStatus `json:"status"`
+	Price        string               `json:"price,omitempty"`
+	RenewalPrice string               `json:"renewal_price,omitempty"`
+	Reason       string               `json:"reason,omitempty"`
+	Source       string               `json:"source,omitempty"`
+	FirstSeen    time.Time            `json:"first_seen"`
+	LastSeen     time.Time            `json:"last_seen"`
+	Checks       int                  `json:"checks"`
+}
+
+// domainHistory returns domain's recorded checks, oldest first, merging
+// consecutive ones that saw the same status and price unless all is set.
+func domainHistory(db *sql.DB, domain string, all bool) ([]historyEntry, error) {
+	rows, err := db.Query(`SELECT status, price, renewal_price, reason, source, checked_at FROM checks WHERE domain = ? ORDER BY checked_at, id`, historyKey(domain))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []historyEntry
+	for rows.Next() {
+		var status string
+		var e historyEntry
+		if err := rows.Scan(&status, &e.Price, &e.RenewalPrice, &e.Reason, &e.Source, &e.FirstSeen); err != nil {
+			return nil, err
+		}
+		e.Status.UnmarshalText([]byte(status))
+		e.LastSeen, e.Checks = e.FirstSeen, 1
+		if n := len(entries); !all && n > 0 && entries[n-1].Status == e.Status && entries[n-1].Price == e.Price {
+			entries[n-1].LastSeen = e.LastSeen
+			entries[n-1].Checks++
+			continue
+		}
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
+
+func runHistory(ctx context.Context, args []string) {
+	fs := flag.NewFlagSet("history", flag.ExitOnError)
+	all := fs.Bool("all", false, "List every check instead of only changes")
+	jsonOut := fs.Bool("json", false, "Print the history as JSON")
+	noColor := fs.Bool("no-color", false, "Disable colored output (also disabled by NO_COLOR or when stdout is not a terminal)")
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, `Usage: domainr history [flags] <domain>
+
+Show how a domain's status and price have changed across every check
+recorded in the local history database, such as premium prices dropping or
+a domain dropping back to availability. Checks are recorded automatically
+unless -no-history is passed.
+
+Flags:
+`)
+		fs.PrintDefaults()
+	}
+	applyConfig(fs)
+	args = parseArgs(fs, args)
+	setColor(*noColor)
+	if len(args) != 1 {
+		fs.Usage()
+		os.Exit(1)
+	}
+	domain := args[0]
+
+	db := openHistory()
+	if db == nil {
+		os.Exit(1)
+	}
+	entries, err := domainHistory(db, domain, *all)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if *jsonOut {
+		if entries == nil {
+			entries = []historyEntry{}
+		}
+		data, _ := json.MarshalIndent(entries, "", "  ")
+		fmt.Println(string(data))
+		return
+	}
+	if len(entries) == 0 {
+		fmt.Fprintf(os.Stderr, "No recorded checks of %s\n", domain)
+		return
+	}
+
+	fmt.Printf("\n  %s%s%s\n\n", colorBold, domain, colorReset)
+	for _, e := range entries {
+		seen := e.FirstSeen.Local().Format(time.DateTime)
+		if e.Checks > 1 {
+			seen += " – " + e.LastSeen.Local().Format(time.DateTime)
+		}
+		line := fmt.Sprintf("  %s  %s%s%-10s%s", seen, statusColor(e.Status), colorBold, e.Status, colorReset)
+		if e.Price != "" {
+			line += "  " + e.Price
+		}
+		if e.Checks > 1 {
+			line += fmt.Sprintf("  %s(%d checks)%s", colorDim, e.Checks, colorReset)
+		}
+		fmt.Println(line)
+	}
+	fmt.Println()
+}
+
+// statusColor returns the color text output uses for a status.
+func statusColor(s domainr.DomainStatus) string {
+	switch s {
+	case domainr.StatusAvailable:
+		return colorGreen
+	case domainr.StatusPremium, domainr.StatusMakeOffer:
+		return colorPurple
+	case domainr.StatusTaken, domainr.StatusReserved:
+		return colorRed
+	default:
+		return colorYellow
+	}
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jpoz/domainr/pkg/domainr"
+)
+
+func TestHistoryRoundTrip(t *testing.T) {
+	db, err := openHistoryAt(filepath.Join(t.TempDir(), "history.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	checked := time.Date(2025, 3, 1, 12, 30, 0, 0, time.UTC)
+	for i, status := range []domainr.DomainStatus{domainr.StatusTaken, domainr.StatusAvailable} {
+		_, err := db.Exec(`INSERT INTO checks (domain, status, price, renewal_price, reason, source, checked_at) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+			historyKey("Example.AI"), status.String(), "$70.00", "", "", domainr.SourceNamecheap, checked.Add(time.Duration(i)*time.Hour))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	entries, err := domainHistory(db, "example.ai", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || entries[1].Status != domainr.StatusAvailable || !entries[0].FirstSeen.Equal(checked) {
+		t.Fatalf("domainHistory() = %+v", entries)
+	}
+
+	checks, err := loadTrendChecks(db, checked.Add(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(checks) != 1 || checks[0].status != domainr.StatusAvailable {
+		t.Errorf("loadTrendChecks() since the first check = %+v", checks)
+	}
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/jpoz/domainr/pkg/domainr"
+)
+
+// backendEnv lists the credentials each API backend reads from the
+// environment.
+var backendEnv = map[string][]string{
+	domainr.SourceNamecheapAPI: {"NAMECHEAP_API_USER", "NAMECHEAP_API_KEY", "NAMECHEAP_CLIENT_IP", "NAMECHEAP_USERNAME"},
+	domainr.SourcePorkbun:      {"PORKBUN_API_KEY", "PORKBUN_SECRET_API_KEY"},
+	domainr.SourceGoDaddy:      {"GODADDY_API_KEY", "GODADDY_API_SECRET", "GODADDY_OTE"},
+}
+
+func runInit(ctx context.Context, args []string) {
+	fs := flag.NewFlagSet("init", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, `Usage: domainr init
+
+Create a config file by answering a few questions: default TLDs, backend and
+its API keys, output format and notifications. Then optionally install the
+browser used for scraping and run a test check. Press enter to accept the
+default shown in brackets.
+
+Flags:
+`)
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	path := configPath()
+	if path == "" {
+		fmt.Fprintln(os.Stderr, "Error: can't find a home directory for the config file")
+		os.Exit(1)
+	}
+	in := bufio.NewReader(os.Stdin)
+	if _, err := os.Stat(path); err == nil && !confirm(in, fmt.Sprintf("%s exists. Replace it?", path), false) {
+		return
+	}
+
+	cfg := make(map[string]any)
+	cfg["tlds"] = splitList(ask(in, "Default TLDs for bare keywords", strings.Join(defaultTLDs, ",")))
+
+	var backends []string
+	for {
+		backends = splitList(ask(in, "Backends, in fallback order ("+strings.Join(domainr.Backends(), ", ")+")", domainr.SourceNamecheap))
+		if len(backends) == 0 {
+			continue
+		}
+		if unknown := slices.IndexFunc(backends, func(b string) bool { return !slices.Contains(domainr.Backends(), b) }); unknown >= 0 {
+			fmt.Fprintf(os.Stderr, "Unknown backend %q\n", backends[unknown])
+			continue
+		}
+		break
+	}
+	cfg["backend"] = backends
+
+	env := make(map[string]any)
+	for _, b := range backends {
+		for _, key := range backendEnv[b] {
+			if value := ask(in, key+" (blank to skip)", ""); value != "" {
+				env[key] = value
+				os.Setenv(key, value)
+			}
+		}
+	}
+	if len(env) > 0 {
+		cfg["env"] = env
+	}
+
+	switch format := ask(in, "Output format (text, json, csv)", "text"); format {
+	case "json":
+		cfg["json"] = true
+	case "csv":
+		cfg["csv"] = "-"
+	}
+	if !confirm(in, "Use colors in the terminal?", true) {
+		cfg["no-color"] = true
+	}
+	if confirm(in, "Raise desktop notifications when domains are available?", false) {
+		cfg["notify"] = true
+	}
+
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(cfg); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	// API keys may be in it
+	if err := os.WriteFile(path, buf.Bytes(), 0o600); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Fprintf(os.Stderr, "Wrote %s\n", path)
+
+	if slices.Contains(backends, domainr.SourceNamecheap) && confirm(in, "Install the browser used to scrape Namecheap now?", true) {
+		if err := domainr.InstallBrowser(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	if confirm(in, "Run a test check of example.com?", true) {
+		setColor(cfg["no-color"] == true)
+		opts := domainr.Options{Headless: true}
+		opts.Backend, opts.Fallbacks = backends[0], backends[1:]
+		checkAndPrint(ctx, []string{"example.com"}, opts, outputConfig{})
+	}
+}
+
+// ask prompts on stderr and reads a line of input, returning def for an
+// empty answer or at the end of input.
+func ask(in *bufio.Reader, question, def string) string {
+	if def != "" {
+		fmt.Fprintf(os.Stderr, "%s [%s]: ", question, def)
+	} else {
+		fmt.Fprintf(os.Stderr, "%s: ", question)
+	}
+	line, err := in.ReadString('\n')
+	if err == io.EOF {
+		fmt.Fprintln(os.Stderr)
+	}
+	if line = strings.TrimSpace(line); line != "" {
+		return line
+	}
+	return def
+}
+
+// confirm asks a yes/no question, returning def for an empty answer.
+func confirm(in *bufio.Reader, question string, def bool) bool {
+	hint := "y/N"
+	if def {
+		hint = "Y/n"
+	}
+	switch strings.ToLower(ask(in, question+" ("+hint+")", "")) {
+	case "y", "yes":
+		return true
+	case "n", "no":
+		return false
+	default:
+		return def
+	}
+}
@@ -0,0 +1,226 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/jpoz/domainr/pkg/domainr"
+)
+
+// dedupeDomains removes case-insensitive duplicates, counting an IDN's
+// Unicode and punycode forms as the same name, while preserving the order of
+// first appearance. It returns the unique domains and the number of
+// duplicates collapsed.
+func dedupeDomains(domains []string) ([]string, int) {
+	seen := make(map[string]bool, len(domains))
+	unique := domains[:0:0]
+	for _, d := range domains {
+		key := strings.ToLower(domainr.ToASCII(d))
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		unique = append(unique, d)
+	}
+	return unique, len(domains) - len(unique)
+}
+
+// expandTLDs turns each bare keyword (a name without a dot) into one domain
+// per TLD. Full domains pass through unchanged.
+func expandTLDs(args []string, tlds []string) []string {
+	var domains []string
+	for _, arg := range args {
+		if strings.Contains(arg, ".") {
+			domains = append(domains, arg)
+			continue
+		}
+		for _, tld := range tlds {
+			domains = append(domains, arg+"."+strings.TrimPrefix(tld, "."))
+		}
+	}
+	return domains
+}
+
+// expandBraces expands shell-style brace groups, so "mybrand.{com,io}"
+// becomes mybrand.com and mybrand.io and "{get,try}mybrand.com" becomes
+// getmybrand.com and trymybrand.com. Groups may nest or repeat; text without
+// braces is returned as is.
+func expandBraces(s string) ([]string, error) {
+	open := strings.IndexByte(s, '{')
+	if open < 0 {
+		if strings.ContainsRune(s, '}') {
+			return nil, fmt.Errorf("unbalanced braces in %q", s)
+		}
+		return []string{s}, nil
+	}
+	prefix := s[:open]
+	if strings.ContainsRune(prefix, '}') {
+		return nil, fmt.Errorf("unbalanced braces in %q", s)
+	}
+
+	// Split the group's top-level alternatives, leaving nested groups whole
+	var alts []string
+	depth, start, end := 0, open+1, -1
+	for i := open; i < len(s) && end < 0; i++ {
+		switch s[i] {
+		case '{':
+			depth++
+		case '}':
+			if depth--; depth == 0 {
+				end = i
+			}
+		case ',':
+			if depth == 1 {
+				alts = append(alts, s[start:i])
+				start = i + 1
+			}
+		}
+	}
+	if end < 0 {
+		return nil, fmt.Errorf("unbalanced braces in %q", s)
+	}
+	alts = append(alts, s[start:end])
+
+	suffixes, err := expandBraces(s[end+1:])
+	if err != nil {
+		return nil, err
+	}
+	var out []string
+	for _, alt := range alts {
+		expanded, err := expandBraces(alt)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range expanded {
+			for _, suffix := range suffixes {
+				out = append(out, prefix+e+suffix)
+			}
+		}
+	}
+	return out, nil
+}
+
+// readDomainList reads domains from r, one or more per line separated by
+// whitespace or commas. Blank lines and #-comments are skipped.
+func readDomainList(r io.Reader) ([]string, error) {
+	var domains []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		for _, field := range strings.FieldsFunc(line, func(c rune) bool {
+			return c == ',' || c == ' ' || c == '\t' || c == '\r'
+		}) {
+			domains = append(domains, field)
+		}
+	}
+	return domains, scanner.Err()
+}
+
+// collectDomains gathers domains from the positional arguments, where "-"
+// means standard input and brace groups are expanded, and from an optional
+// list file.
+func collectDomains(args []string, file string) ([]string, error) {
+	var domains []string
+	for _, arg := range args {
+		if arg != "-" {
+			expanded, err := expandBraces(arg)
+			if err != nil {
+				return nil, err
+			}
+			domains = append(domains, expanded...)
+			continue
+		}
+		list, err := readDomainList(os.Stdin)
+		if err != nil {
+			return nil, fmt.Errorf("reading stdin: %w", err)
+		}
+		domains = append(domains, list...)
+	}
+
+	if file != "" {
+		f, err := os.Open(file)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		list, err := readDomainList(f)
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", file, err)
+		}
+		domains = append(domains, list...)
+	}
+	return domains, nil
+}
+
+// variantPrefixes and variantSuffixes are the affixes -variants adds to each
+// name.
+var (
+	variantPrefixes = []string{"get", "try", "use"}
+	variantSuffixes = []string{"er", "ify", "ly"}
+)
+
+// expandVariants adds common variants of each domain's name after it: its
+// plural, get-/try-/use- prefixes with and without a hyphen, and -er, -ify
+// and -ly suffixes. It also returns why each variant was generated, keyed by
+// lowercased domain. Duplicates are left for dedupeDomains.
+func expandVariants(domains []string) ([]string, map[string]string) {
+	var out []string
+	why := make(map[string]string)
+	for _, d := range domains {
+		out = append(out, d)
+		name, tld, _ := strings.Cut(d, ".")
+		add := func(variant, reason string) {
+			v := variant + "." + tld
+			if _, seen := why[strings.ToLower(v)]; !seen && validDomain(v) {
+				why[strings.ToLower(v)] = reason
+				out = append(out, v)
+			}
+		}
+		add(plural(name), fmt.Sprintf("plural of %q", name))
+		for _, p := range variantPrefixes {
+			add(p+name, fmt.Sprintf("prefix %q + %q", p, name))
+			add(p+"-"+name, fmt.Sprintf("prefix %q + %q, hyphenated", p, name))
+		}
+		for _, s := range variantSuffixes {
+			add(withSuffix(name, s), fmt.Sprintf("%q + suffix %q", name, s))
+		}
+	}
+	return out, why
+}
+
+// plural returns the English plural of a word by the regular rules.
+func plural(word string) string {
+	switch {
+	case hasAnySuffix(word, "s", "x", "z", "ch", "sh"):
+		return word + "es"
+	case len(word) > 1 && strings.HasSuffix(word, "y") && !strings.ContainsRune("aeiou", rune(word[len(word)-2])):
+		return word[:len(word)-1] + "ies"
+	default:
+		return word + "s"
+	}
+}
+
+// withSuffix adds suffix to word, dropping a final "e" before a suffix
+// starting with a vowel (make -> maker) and turning a final consonant + "y"
+// into "i" (tidy -> tidily, not tidiify).
+func withSuffix(word, suffix string) string {
+	if strings.HasSuffix(word, "e") && strings.ContainsRune("aeiou", rune(suffix[0])) {
+		return word[:len(word)-1] + suffix
+	}
+	if len(word) > 1 && strings.HasSuffix(word, "y") && !strings.ContainsRune("aeiou", rune(word[len(word)-2])) {
+		return word[:len(word)-1] + "i" + strings.TrimPrefix(suffix, "i")
+	}
+	return word + suffix
+}
+
+func hasAnySuffix(s string, suffixes ...string) bool {
+	for _, suffix := range suffixes {
+		if strings.HasSuffix(s, suffix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestExpandBraces(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    []string
+		wantErr bool
+	}{
+		{in: "mybrand.com", want: []string{"mybrand.com"}},
+		{in: "mybrand.{com,io}", want: []string{"mybrand.com", "mybrand.io"}},
+		{in: "{get,try}mybrand.com", want: []string{"getmybrand.com", "trymybrand.com"}},
+		{in: "{get,}app.{io,ai}", want: []string{"getapp.io", "getapp.ai", "app.io", "app.ai"}},
+		{in: "my{brand,{co,hq}}.com", want: []string{"mybrand.com", "myco.com", "myhq.com"}},
+		{in: "{solo}.com", want: []string{"solo.com"}},
+		{in: "mybrand.{com,io", wantErr: true},
+		{in: "mybrand.com}", wantErr: true},
+		{in: "}{a,b}.com", wantErr: true},
+		{in: "{a,{b}.com", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := expandBraces(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expandBraces() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("expandBraces() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
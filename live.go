@@ -0,0 +1,181 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/jpoz/domainr/pkg/domainr"
+)
+
+// liveRows is the most pending rows shown below the results, so that the
+// pending region, which is redrawn with cursor movements, fits on screen.
+const liveRows = 20
+
+// spinnerFrames animate pending rows.
+var spinnerFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
+// liveSink renders a run in place on a terminal: every domain starts as a
+// pending row with a spinner, and a guess when -preview supplied one, below
+// the results written so far; each is replaced by its result as it arrives.
+// The pending region is redrawn by moving the cursor back over it, so
+// messages during the run must be written to logWriter, which prints them
+// above it, rather than straight to stderr.
+type liveSink struct {
+	*textSink
+
+	mu      sync.Mutex
+	pending []domainr.DomainResult
+	// drawn is how many lines the pending region takes on screen
+	drawn int
+	frame int
+	// log is where logWriter's lines go; logged holds a partial line
+	log    io.Writer
+	logged []byte
+
+	stop chan struct{}
+	done sync.WaitGroup
+}
+
+func newLiveSink(w io.Writer, width int, pending []domainr.DomainResult) *liveSink {
+	s := &liveSink{textSink: newTextSink(w, width), pending: pending, log: os.Stderr, stop: make(chan struct{})}
+	// The text sink's leading blank line goes above the pending rows
+	fmt.Fprintln(w)
+	s.started = true
+	s.draw()
+
+	s.done.Add(1)
+	go func() {
+		defer s.done.Done()
+		ticker := time.NewTicker(100 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-s.stop:
+				return
+			case <-ticker.C:
+			}
+			s.mu.Lock()
+			s.frame++
+			s.redraw(nil)
+			s.mu.Unlock()
+		}
+	}()
+	return s
+}
+
+func (s *liveSink) Write(r domainr.DomainResult) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !r.Related {
+		s.removePending(r.Domain)
+	}
+	var err error
+	s.redraw(func() { err = s.textSink.Write(r) })
+	return err
+}
+
+// skip removes the pending row of a result that won't be written, such as
+// one hidden by -budget.
+func (s *liveSink) skip(r domainr.DomainResult) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !r.Related {
+		s.removePending(r.Domain)
+		s.redraw(nil)
+	}
+}
+
+func (s *liveSink) removePending(domain string) {
+	key := strings.ToLower(domainr.ToASCII(domain))
+	s.pending = slices.DeleteFunc(s.pending, func(p domainr.DomainResult) bool {
+		return strings.ToLower(domainr.ToASCII(p.Domain)) == key
+	})
+}
+
+func (s *liveSink) Close() error {
+	close(s.stop)
+	s.done.Wait()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pending = nil
+	s.redraw(func() {
+		if len(s.logged) > 0 {
+			s.log.Write(s.logged)
+			s.logged = nil
+		}
+	})
+	return s.textSink.Close()
+}
+
+// logWriter returns a writer for warnings and progress messages during the
+// run. Each whole line written to it is printed above the pending region.
+func (s *liveSink) logWriter() io.Writer {
+	return liveLog{s}
+}
+
+type liveLog struct{ s *liveSink }
+
+func (l liveLog) Write(p []byte) (int, error) {
+	s := l.s
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.logged = append(s.logged, p...)
+	if i := bytes.LastIndexByte(s.logged, '\n'); i >= 0 {
+		lines := s.logged[:i+1]
+		s.redraw(func() { s.log.Write(lines) })
+		s.logged = slices.Clone(s.logged[i+1:])
+	}
+	return len(p), nil
+}
+
+// redraw erases the pending region, calls write, if any, to print above it,
+// and draws the region again. s.mu must be held.
+func (s *liveSink) redraw(write func()) {
+	if s.drawn > 0 {
+		fmt.Fprintf(s.w, "\033[%dA\033[J", s.drawn)
+		s.drawn = 0
+	}
+	if write != nil {
+		write()
+	}
+	s.draw()
+}
+
+// draw prints the pending rows, each with a spinner and its guess dimmed.
+func (s *liveSink) draw() {
+	spinner := spinnerFrames[s.frame%len(spinnerFrames)]
+	for i, p := range s.pending {
+		if i == liveRows {
+			fmt.Fprintf(s.w, "  %s… and %d more%s\n", colorDim, len(s.pending)-i, colorReset)
+			s.drawn++
+			break
+		}
+		padded := p.Domain
+		if n := utf8.RuneCountInString(p.Domain); n < s.width {
+			padded += strings.Repeat(" ", s.width-n)
+		}
+		fmt.Fprintf(s.w, "  %s  %s%s %s%s\n", padded, colorYellow, spinner, colorDim+describeGuess(p), colorReset)
+		s.drawn++
+	}
+}
+
+// describeGuess says what is known about a pending domain: a -preview guess
+// from DNS or the cache, or nothing yet.
+func describeGuess(g domainr.DomainResult) string {
+	switch {
+	case g.Cached:
+		return fmt.Sprintf("%-10s (cached, checking…)", g.Status)
+	case g.Status == domainr.StatusUnknown:
+		return "checking…"
+	default:
+		return fmt.Sprintf("%-10s (%s guess, checking…)", g.Status.String()+"?", g.Source)
+	}
+}
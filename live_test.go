@@ -0,0 +1,40 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/jpoz/domainr/pkg/domainr"
+)
+
+func TestLiveSinkLog(t *testing.T) {
+	var out, log bytes.Buffer
+	s := newLiveSink(&out, 12, []domainr.DomainResult{{Domain: "a.com"}, {Domain: "b.com"}})
+	s.log = &log
+
+	w := s.logWriter()
+	fmt.Fprint(w, "Retrying a.com")
+	if log.Len() != 0 {
+		t.Errorf("partial line printed: %q", log.String())
+	}
+	fmt.Fprint(w, " in 5s\nWarning: ")
+	if got := log.String(); got != "Retrying a.com in 5s\n" {
+		t.Errorf("log = %q, want the whole line only", got)
+	}
+
+	s.skip(domainr.DomainResult{Domain: "A.com"})
+	s.mu.Lock()
+	pending := len(s.pending)
+	s.mu.Unlock()
+	if pending != 1 {
+		t.Errorf("pending rows after skip = %d, want 1", pending)
+	}
+
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if got := log.String(); got != "Retrying a.com in 5s\nWarning: " {
+		t.Errorf("log after close = %q, want the partial line flushed", got)
+	}
+}
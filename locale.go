@@ -0,0 +1,162 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/jpoz/domainr/pkg/domainr"
+)
+
+// localeTLDs maps a language code to the ccTLDs of markets that speak it.
+var localeTLDs = map[string][]string{
+	"de": {"de", "at", "ch"},
+	"fr": {"fr", "be"},
+	"es": {"es", "mx"},
+	"it": {"it"},
+	"nl": {"nl"},
+	"pt": {"pt", "com.br"},
+}
+
+// localeDictionary is a small offline dictionary of words common in product
+// names, keyed by English word and then language code.
+var localeDictionary = map[string]map[string]string{
+	"bear":   {"de": "bär", "fr": "ours", "es": "oso", "it": "orso", "nl": "beer", "pt": "urso"},
+	"bird":   {"de": "vogel", "fr": "oiseau", "es": "pájaro", "it": "uccello", "nl": "vogel", "pt": "pássaro"},
+	"blue":   {"de": "blau", "fr": "bleu", "es": "azul", "it": "blu", "nl": "blauw", "pt": "azul"},
+	"book":   {"de": "buch", "fr": "livre", "es": "libro", "it": "libro", "nl": "boek", "pt": "livro"},
+	"bridge": {"de": "brücke", "fr": "pont", "es": "puente", "it": "ponte", "nl": "brug", "pt": "ponte"},
+	"cloud":  {"de": "wolke", "fr": "nuage", "es": "nube", "it": "nuvola", "nl": "wolk", "pt": "nuvem"},
+	"dream":  {"de": "traum", "fr": "rêve", "es": "sueño", "it": "sogno", "nl": "droom", "pt": "sonho"},
+	"fast":   {"de": "schnell", "fr": "rapide", "es": "rápido", "it": "veloce", "nl": "snel", "pt": "rápido"},
+	"fire":   {"de": "feuer", "fr": "feu", "es": "fuego", "it": "fuoco", "nl": "vuur", "pt": "fogo"},
+	"fox":    {"de": "fuchs", "fr": "renard", "es": "zorro", "it": "volpe", "nl": "vos", "pt": "raposa"},
+	"garden": {"de": "garten", "fr": "jardin", "es": "jardín", "it": "giardino", "nl": "tuin", "pt": "jardim"},
+	"green":  {"de": "grün", "fr": "vert", "es": "verde", "it": "verde", "nl": "groen", "pt": "verde"},
+	"home":   {"de": "heim", "fr": "maison", "es": "casa", "it": "casa", "nl": "thuis", "pt": "casa"},
+	"light":  {"de": "licht", "fr": "lumière", "es": "luz", "it": "luce", "nl": "licht", "pt": "luz"},
+	"moon":   {"de": "mond", "fr": "lune", "es": "luna", "it": "luna", "nl": "maan", "pt": "lua"},
+	"red":    {"de": "rot", "fr": "rouge", "es": "rojo", "it": "rosso", "nl": "rood", "pt": "vermelho"},
+	"shop":   {"de": "laden", "fr": "boutique", "es": "tienda", "it": "negozio", "nl": "winkel", "pt": "loja"},
+	"sky":    {"de": "himmel", "fr": "ciel", "es": "cielo", "it": "cielo", "nl": "hemel", "pt": "céu"},
+	"smart":  {"de": "klug", "fr": "malin", "es": "listo", "it": "furbo", "nl": "slim", "pt": "esperto"},
+	"star":   {"de": "stern", "fr": "étoile", "es": "estrella", "it": "stella", "nl": "ster", "pt": "estrela"},
+	"sun":    {"de": "sonne", "fr": "soleil", "es": "sol", "it": "sole", "nl": "zon", "pt": "sol"},
+	"tree":   {"de": "baum", "fr": "arbre", "es": "árbol", "it": "albero", "nl": "boom", "pt": "árvore"},
+	"water":  {"de": "wasser", "fr": "eau", "es": "agua", "it": "acqua", "nl": "water", "pt": "água"},
+	"wolf":   {"de": "wolf", "fr": "loup", "es": "lobo", "it": "lupo", "nl": "wolf", "pt": "lobo"},
+}
+
+// transliterations folds non-ASCII letters into their conventional ASCII
+// spelling. German umlauts expand (ü -> ue); other accents are dropped.
+var transliterations = strings.NewReplacer(
+	"ä", "ae", "ö", "oe", "ü", "ue", "ß", "ss",
+	"à", "a", "á", "a", "â", "a", "ã", "a",
+	"ç", "c",
+	"è", "e", "é", "e", "ê", "e", "ë", "e",
+	"ì", "i", "í", "i", "î", "i", "ï", "i",
+	"ñ", "n",
+	"ò", "o", "ó", "o", "ô", "o", "õ", "o",
+	"ù", "u", "ú", "u", "û", "u",
+)
+
+func runLocalize(ctx context.Context, args []string) {
+	fs := flag.NewFlagSet("localize", flag.ExitOnError)
+	langs := fs.String("langs", strings.Join(localeLanguages(), ","), "Comma-separated languages to generate variants for")
+	visible := fs.Bool("visible", false, "Show the browser window (useful for debugging)")
+	debug := fs.Bool("debug", false, "Log scraping diagnostics to stderr")
+	jsonOut := fs.Bool("json", false, "Print results as JSON")
+	noColor := fs.Bool("no-color", false, "Disable colored output (also disabled by NO_COLOR or when stdout is not a terminal)")
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: domainr localize [flags] <name>\n\nTranslate an English base name (e.g. bluefox or blue-fox) with an offline\ndictionary and check each translation across the ccTLDs of its markets.\n\nFlags:\n")
+		fs.PrintDefaults()
+	}
+	applyConfig(fs)
+	fs.Parse(args)
+	setColor(*noColor)
+
+	if fs.NArg() != 1 {
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	var domains []string
+	why := make(map[string]string)
+	for _, lang := range splitList(*langs) {
+		tlds, ok := localeTLDs[lang]
+		if !ok {
+			fmt.Fprintf(os.Stderr, "Error: unsupported language %q (have %s)\n", lang, strings.Join(localeLanguages(), ", "))
+			os.Exit(1)
+		}
+		variant, ok := localizeName(fs.Arg(0), lang)
+		if !ok {
+			fmt.Fprintf(os.Stderr, "No %s translation for %q, skipping\n", lang, fs.Arg(0))
+			continue
+		}
+		fmt.Fprintf(os.Stderr, "%s: %s\n", lang, variant)
+		for _, tld := range tlds {
+			domains = append(domains, variant+"."+tld)
+			why[variant+"."+tld] = fmt.Sprintf("%s translation of %q", lang, fs.Arg(0))
+		}
+	}
+	if len(domains) == 0 {
+		fmt.Fprintln(os.Stderr, "Error: no variants to check")
+		os.Exit(1)
+	}
+
+	checkAndPrint(ctx, prepareDomains(domains), domainr.Options{Headless: !*visible, Debug: *debug}, outputConfig{JSON: *jsonOut, Rationale: why})
+}
+
+// localizeName translates each dictionary word of name into lang and returns
+// the ASCII-transliterated result. Words may be hyphenated or run together.
+func localizeName(name, lang string) (string, bool) {
+	var translated []string
+	for _, part := range strings.Split(strings.ToLower(name), "-") {
+		words, ok := segmentWords(part)
+		if !ok {
+			return "", false
+		}
+		var joined string
+		for _, w := range words {
+			joined += localeDictionary[w][lang]
+		}
+		translated = append(translated, transliterations.Replace(joined))
+	}
+	return strings.Join(translated, "-"), true
+}
+
+// segmentWords splits s into dictionary words, preferring the fewest words.
+func segmentWords(s string) ([]string, bool) {
+	// best[i] holds the shortest segmentation of s[:i]
+	best := make([][]string, len(s)+1)
+	best[0] = []string{}
+	for end := 1; end <= len(s); end++ {
+		for start := 0; start < end; start++ {
+			if best[start] == nil {
+				continue
+			}
+			if _, ok := localeDictionary[s[start:end]]; !ok {
+				continue
+			}
+			if best[end] == nil || len(best[start])+1 < len(best[end]) {
+				best[end] = append(append([]string{}, best[start]...), s[start:end])
+			}
+		}
+	}
+	if len(s) == 0 || best[len(s)] == nil {
+		return nil, false
+	}
+	return best[len(s)], true
+}
+
+func localeLanguages() []string {
+	langs := make([]string, 0, len(localeTLDs))
+	for lang := range localeTLDs {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs)
+	return langs
+}
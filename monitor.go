@@ -0,0 +1,369 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/jpoz/domainr/pkg/domainr"
+)
+
+// watchlistEntry is a domain on the monitor watchlist with the status it had
+// when last checked.
+type watchlistEntry struct {
+	Domain    string               `json:"domain"`
+	Note      string               `json:"note,omitempty"`
+	AddedAt   time.Time            `json:"added_at"`
+	Status    domainr.DomainStatus `json:"status"`
+	CheckedAt *time.Time           `json:"checked_at,omitempty"`
+}
+
+func watchlistPath() string {
+	return filepath.Join(dataDir(), "watchlist.json")
+}
+
+// loadWatchlist reads the watchlist, which is empty until a domain is added.
+func loadWatchlist() ([]watchlistEntry, error) {
+	data, err := os.ReadFile(watchlistPath())
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var list []watchlistEntry
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, fmt.Errorf("%s: %w", watchlistPath(), err)
+	}
+	return list, nil
+}
+
+func saveWatchlist(list []watchlistEntry) error {
+	data, err := json.MarshalIndent(list, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dataDir(), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(watchlistPath(), append(data, '\n'), 0o644)
+}
+
+func runMonitor(ctx context.Context, args []string) {
+	usage := func() {
+		fmt.Fprint(os.Stderr, `Usage: domainr monitor add [-note text] <domain> [...]
+       domainr monitor remove <domain> [...]
+       domainr monitor list [-json]
+       domainr monitor run [-schedule 6h] [flags]
+
+Keep a standing watchlist of domains you hope will drop. The list and each
+domain's last status are kept in the data directory, so it survives
+restarts and edits while monitor run is going: each round checks whatever
+is on the list then. A domain going from taken to available is printed and
+sent to -notify and -slack-webhook. Run "domainr monitor <command> -h" for
+each command's flags.
+`)
+	}
+	if len(args) == 0 {
+		usage()
+		os.Exit(1)
+	}
+	switch args[0] {
+	case "add":
+		runMonitorAdd(args[1:])
+	case "remove", "rm":
+		runMonitorRemove(args[1:])
+	case "list", "ls":
+		runMonitorList(args[1:])
+	case "run":
+		runMonitorRun(ctx, args[1:])
+	case "-h", "-help", "--help", "help":
+		usage()
+	default:
+		fmt.Fprintf(os.Stderr, "Error: unknown monitor command %q\n", args[0])
+		usage()
+		os.Exit(1)
+	}
+}
+
+func runMonitorAdd(args []string) {
+	fs := flag.NewFlagSet("monitor add", flag.ExitOnError)
+	note := fs.String("note", "", "Note to keep with the domains, e.g. why you want them")
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, `Usage: domainr monitor add [-note text] <domain|-> [...]
+
+Add domains to the watchlist. Brace groups expand, and "-" reads domains
+from standard input.
+
+Flags:
+`)
+		fs.PrintDefaults()
+	}
+	applyConfig(fs)
+	domains, err := collectDomains(parseArgs(fs, args), "")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if len(domains) == 0 {
+		fs.Usage()
+		os.Exit(1)
+	}
+	domains = prepareDomains(domains)
+
+	list, err := loadWatchlist()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	onList := make(map[string]bool, len(list))
+	for _, e := range list {
+		onList[strings.ToLower(domainr.ToASCII(e.Domain))] = true
+	}
+	added := 0
+	for _, d := range domains {
+		if onList[strings.ToLower(domainr.ToASCII(d))] {
+			continue
+		}
+		list = append(list, watchlistEntry{Domain: d, Note: *note, AddedAt: time.Now()})
+		added++
+	}
+	if err := saveWatchlist(list); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Fprintf(os.Stderr, "Added %d domain(s) to the watchlist", added)
+	if skipped := len(domains) - added; skipped > 0 {
+		fmt.Fprintf(os.Stderr, ", %d already on it", skipped)
+	}
+	fmt.Fprintln(os.Stderr)
+}
+
+func runMonitorRemove(args []string) {
+	fs := flag.NewFlagSet("monitor remove", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, `Usage: domainr monitor remove <domain> [...]
+
+Remove domains from the watchlist.
+
+Flags:
+`)
+		fs.PrintDefaults()
+	}
+	applyConfig(fs)
+	domains := parseArgs(fs, args)
+	if len(domains) == 0 {
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	list, err := loadWatchlist()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	remove := make(map[string]bool, len(domains))
+	for _, d := range domains {
+		remove[strings.ToLower(domainr.ToASCII(d))] = true
+	}
+	kept := list[:0]
+	for _, e := range list {
+		if !remove[strings.ToLower(domainr.ToASCII(e.Domain))] {
+			kept = append(kept, e)
+		}
+	}
+	removed := len(list) - len(kept)
+	if removed == 0 {
+		fmt.Fprintln(os.Stderr, "Error: none of those domains are on the watchlist")
+		os.Exit(1)
+	}
+	if err := saveWatchlist(kept); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Fprintf(os.Stderr, "Removed %d domain(s) from the watchlist\n", removed)
+}
+
+func runMonitorList(args []string) {
+	fs := flag.NewFlagSet("monitor list", flag.ExitOnError)
+	jsonOut := fs.Bool("json", false, "Print the watchlist as JSON")
+	noColor := fs.Bool("no-color", false, "Disable colored output")
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, `Usage: domainr monitor list [-json]
+
+Show the watchlist with each domain's status when last checked.
+
+Flags:
+`)
+		fs.PrintDefaults()
+	}
+	applyConfig(fs)
+	fs.Parse(args)
+	setColor(*noColor)
+
+	list, err := loadWatchlist()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if *jsonOut {
+		if list == nil {
+			list = []watchlistEntry{}
+		}
+		data, _ := json.MarshalIndent(list, "", "  ")
+		fmt.Println(string(data))
+		return
+	}
+	if len(list) == 0 {
+		fmt.Fprintln(os.Stderr, "The watchlist is empty; add domains with domainr monitor add")
+		return
+	}
+
+	names := make([]string, len(list))
+	for i, e := range list {
+		names[i] = e.Domain
+	}
+	width := longestDomain(names)
+	for _, e := range list {
+		checked := "never checked"
+		if e.CheckedAt != nil {
+			checked = "checked " + formatAge(time.Since(*e.CheckedAt)) + " ago"
+		}
+		color := colorDim
+		switch {
+		case isAvailable(e.Status):
+			color = colorGreen
+		case e.Status == domainr.StatusTaken:
+			color = colorRed
+		}
+		fmt.Printf("  %s%-*s%s  %s%-10s%s  %s%s%s", colorBold, width, e.Domain, colorReset,
+			color, e.Status, colorReset, colorDim, checked, colorReset)
+		if e.Note != "" {
+			fmt.Printf("  %s", e.Note)
+		}
+		fmt.Println()
+	}
+}
+
+func runMonitorRun(ctx context.Context, args []string) {
+	fs := flag.NewFlagSet("monitor run", flag.ExitOnError)
+	schedule := fs.String("schedule", "6h", "How often to check the watchlist: once, hourly, daily, weekly, or a duration like 12h")
+	backend := fs.String("backend", domainr.SourceNamecheap, "How to check domains: namecheap (browser scraping), namecheap-api, porkbun, godaddy, or rdap; a comma-separated list falls back to each next backend for domains the previous one couldn't check")
+	visible := fs.Bool("visible", false, "Show the browser window (useful for debugging)")
+	jsonOut := fs.Bool("json", false, "Print status changes as JSON lines")
+	notify := fs.Bool("notify", false, "Raise a desktop notification when watched domains become available")
+	slack := fs.String("slack-webhook", "", "Post watched domains that become available to a Slack incoming webhook `URL`")
+	webhook := fs.String("webhook", "", "POST each status change as JSON to `URL`")
+	noColor := fs.Bool("no-color", false, "Disable colored output")
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, `Usage: domainr monitor run [flags]
+
+Check every domain on the watchlist now and then on a schedule, reporting
+domains that went from taken to available since they were last checked,
+even by an earlier run. With -schedule once it checks a single round and
+exits, for running from cron.
+
+Flags:
+`)
+		fs.PrintDefaults()
+	}
+	applyConfig(fs)
+	fs.Parse(args)
+	setColor(*noColor)
+
+	interval, err := parseSchedule(*schedule)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	opts := domainr.Options{Headless: !*visible}
+	opts.Backend, opts.Fallbacks = splitBackends(*backend)
+	out := outputConfig{JSON: *jsonOut, Notify: *notify, Slack: *slack, Webhook: *webhook}
+	alerts := newAlerter(nil, 0, 0, out)
+
+	for {
+		if err := monitorRound(ctx, opts, out, alerts); err != nil {
+			if ctx.Err() != nil {
+				os.Exit(130)
+			}
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			if interval == 0 {
+				os.Exit(1)
+			}
+		}
+		if interval == 0 {
+			return
+		}
+
+		fmt.Fprintf(os.Stderr, "Next check at %s\n", time.Now().Add(interval).Format(time.DateTime))
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+	}
+}
+
+// monitorRound checks the watchlist as it is now, reports domains that
+// became available, and saves each domain's new status.
+func monitorRound(ctx context.Context, opts domainr.Options, out outputConfig, alerts *alerter) error {
+	list, err := loadWatchlist()
+	if err != nil {
+		return err
+	}
+	if len(list) == 0 {
+		fmt.Fprintln(os.Stderr, "The watchlist is empty; add domains with domainr monitor add")
+		return nil
+	}
+	domains := make([]string, len(list))
+	last := make(map[string]domainr.DomainStatus, len(list))
+	for i, e := range list {
+		domains[i] = e.Domain
+		last[strings.ToLower(e.Domain)] = e.Status
+	}
+
+	results, checkErr := checkDomains(ctx, domains, opts)
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	checked := make(map[string]domainr.DomainResult, len(results))
+	available := 0
+	for _, r := range results {
+		// A failed check says nothing about whether the status changed
+		if r.Related || r.Status == domainr.StatusUnknown {
+			continue
+		}
+		key := strings.ToLower(r.Domain)
+		checked[key] = r
+		if isAvailable(r.Status) {
+			available++
+		}
+		if last[key] == domainr.StatusTaken && isAvailable(r.Status) {
+			c := statusChange{Domain: r.Domain, From: last[key], To: r.Status, Price: r.Price, ChangedAt: r.CheckedAt}
+			printChange(c, out)
+			alerts.change(c)
+		}
+	}
+	alerts.roundDone()
+
+	// The list may have been edited during the round
+	if list, err = loadWatchlist(); err != nil {
+		return err
+	}
+	for i, e := range list {
+		if r, ok := checked[strings.ToLower(e.Domain)]; ok {
+			list[i].Status, list[i].CheckedAt = r.Status, &r.CheckedAt
+		}
+	}
+	if err := saveWatchlist(list); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "Checked %d watched domain(s), %d available\n", len(checked), available)
+	return checkErr
+}
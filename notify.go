@@ -0,0 +1,81 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/jpoz/domainr/pkg/domainr"
+)
+
+// windowsNotifyScript shows a tray balloon with the title and message passed
+// in the environment, which avoids quoting them into the script.
+const windowsNotifyScript = `Add-Type -AssemblyName System.Windows.Forms
+$n = New-Object System.Windows.Forms.NotifyIcon
+$n.Icon = [System.Drawing.SystemIcons]::Information
+$n.Visible = $true
+$n.ShowBalloonTip(10000, $env:DOMAINR_NOTIFY_TITLE, $env:DOMAINR_NOTIFY_MESSAGE, 'Info')
+Start-Sleep -Seconds 10
+$n.Dispose()`
+
+// desktopNotify raises a native desktop notification: Notification Center on
+// macOS, notify-send (libnotify) on Linux, and a tray balloon on Windows.
+func desktopNotify(title, message string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("osascript",
+			"-e", "on run argv",
+			"-e", "display notification (item 2 of argv) with title (item 1 of argv)",
+			"-e", "end run",
+			title, message)
+	case "windows":
+		cmd = exec.Command("powershell", "-NoProfile", "-NonInteractive", "-Command", windowsNotifyScript)
+		cmd.Env = append(os.Environ(), "DOMAINR_NOTIFY_TITLE="+title, "DOMAINR_NOTIFY_MESSAGE="+message)
+		// The balloon needs the script to stay alive while it's shown
+		return cmd.Start()
+	default:
+		cmd = exec.Command("notify-send", "--app-name=domainr", title, message)
+	}
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("desktop notification: %w: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// notifyAvailable raises one notification listing domains, reporting
+// failures on stderr without stopping the run.
+func notifyAvailable(domains []string) {
+	if len(domains) == 0 {
+		return
+	}
+	title := domains[0] + " is available"
+	if len(domains) > 1 {
+		title = fmt.Sprintf("%d domains are available", len(domains))
+	}
+	if err := desktopNotify(title, strings.Join(domains, ", ")); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+	}
+}
+
+// notifySink collects available results and raises a desktop notification
+// for them on close.
+type notifySink struct {
+	resultSink
+	available []string
+}
+
+func (s *notifySink) Write(r domainr.DomainResult) error {
+	if isAvailable(r.Status) && !r.Related {
+		s.available = append(s.available, r.Domain)
+	}
+	return s.resultSink.Write(r)
+}
+
+func (s *notifySink) Close() error {
+	err := s.resultSink.Close()
+	notifyAvailable(s.available)
+	return err
+}
@@ -0,0 +1,612 @@
+package main
+
+import (
+	"cmp"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/jpoz/domainr/pkg/domainr"
+)
+
+// outputConfig selects how results are written.
+type outputConfig struct {
+	JSON bool
+	// ScriptFilter prints Alfred/Raycast script filter JSON instead.
+	ScriptFilter bool
+	// CSV is where to write CSV: "-" replaces the normal output on stdout,
+	// a path writes a file alongside it, and "" disables CSV.
+	CSV string
+	// TLDInfo attaches registry metadata to each result.
+	TLDInfo bool
+	// Compare attaches other registrars' prices to available results:
+	// Porkbun's by TLD, and GoDaddy's quotes by lowercased ASCII domain when
+	// GoDaddy API keys are set.
+	Compare        domainr.PriceTable
+	CompareGoDaddy map[string]string
+	// Budget hides results priced above their TLD's budget.
+	Budget budgetFlag
+	// FlagOverBudget marks results over Budget instead of hiding them.
+	FlagOverBudget bool
+	// Contact follows the results with published contacts and an inquiry
+	// template for each taken domain.
+	Contact bool
+	// IDN is how internationalized domains are displayed: "ascii" for
+	// punycode, "unicode" for native script, or "" for the form checked.
+	IDN string
+	// Rationale maps lowercased domains to why they were generated, for
+	// commands that generate candidates.
+	Rationale map[string]string
+	// Notify raises a desktop notification when domains are available.
+	Notify bool
+	// Slack is an incoming webhook URL to post results to.
+	Slack string
+	// Sign is where to write a signature of the JSON output, made with the
+	// key from domainr keys.
+	Sign string
+	// Webhook is a URL to post the result set, or in watch mode status
+	// changes, to as JSON.
+	Webhook string
+	// Preview shows quick guesses for every domain before checking them,
+	// when writing text to a terminal.
+	Preview bool
+
+	// live renders text in place, with a pending row for every domain;
+	// checkAndPrint sets it on terminals
+	live *liveSink
+}
+
+// sink returns a sink for the configured formats. width is the domain column
+// width used by the text format.
+func (c outputConfig) sink(w io.Writer, width int) (resultSink, error) {
+	s, err := c.formatSink(w, width)
+	if err != nil {
+		return nil, err
+	}
+	if c.IDN != "" {
+		s = idnSink{s, c.IDN}
+	}
+	if c.Rationale != nil {
+		s = rationaleSink{s, c.Rationale}
+	}
+	if c.TLDInfo {
+		s = tldInfoSink{s}
+	}
+	if c.Compare != nil {
+		s = compareSink{s, c.Compare, c.CompareGoDaddy}
+	}
+	if c.Notify {
+		s = &notifySink{resultSink: s}
+	}
+	if c.Slack != "" {
+		s = &slackSink{resultSink: s, url: c.Slack}
+	}
+	if c.Webhook != "" {
+		s = &webhookSink{resultSink: s, url: c.Webhook}
+	}
+	if len(c.Budget) > 0 {
+		b := &budgetSink{resultSink: s, budget: c.Budget, flag: c.FlagOverBudget}
+		if c.live != nil {
+			b.hide = c.live.skip
+		}
+		s = b
+	}
+	return s, nil
+}
+
+func (c outputConfig) formatSink(w io.Writer, width int) (resultSink, error) {
+	if c.CSV == "-" {
+		return newCSVSink(nopCloser{w}), nil
+	}
+	if c.ScriptFilter {
+		return &scriptFilterSink{w: w}, nil
+	}
+
+	var main resultSink = newTextSink(w, width)
+	if c.live != nil {
+		main = c.live
+	}
+	if c.JSON {
+		main = &jsonSink{w: w}
+		if c.Sign != "" {
+			var err error
+			if main, err = newSigningJSONSink(w, c.Sign); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if c.CSV == "" {
+		return main, nil
+	}
+
+	f, err := os.Create(c.CSV)
+	if err != nil {
+		return nil, err
+	}
+	return multiSink{main, newCSVSink(f)}, nil
+}
+
+// outreachWriter is where the -contact section goes: stdout after text
+// results, stderr when stdout carries JSON or CSV.
+func (c outputConfig) outreachWriter() io.Writer {
+	if c.JSON || c.ScriptFilter || c.CSV == "-" {
+		return os.Stderr
+	}
+	return os.Stdout
+}
+
+// csvFlag is the -csv flag: a file to also write CSV to, or "-" for CSV
+// instead of the normal output on stdout. It always takes a value, so
+// "-csv out.csv example.com" can't mistake the file for a domain.
+type csvFlag struct{ dest *string }
+
+func (f csvFlag) String() string {
+	if f.dest == nil {
+		return ""
+	}
+	return *f.dest
+}
+
+func (f csvFlag) Set(s string) error {
+	if s == "" {
+		return errors.New(`expected a file, or "-" for stdout`)
+	}
+	*f.dest = s
+	return nil
+}
+
+// resultSink receives results one at a time as they become available.
+type resultSink interface {
+	Write(r domainr.DomainResult) error
+	Close() error
+}
+
+// textSink writes colored, column-aligned result lines.
+type textSink struct {
+	w         io.Writer
+	width     int
+	started   bool
+	inRelated bool
+}
+
+func newTextSink(w io.Writer, width int) *textSink {
+	return &textSink{w: w, width: width}
+}
+
+func (s *textSink) Write(r domainr.DomainResult) error {
+	if !s.started {
+		fmt.Fprintln(s.w)
+		s.started = true
+	}
+	if r.Related && !s.inRelated {
+		fmt.Fprintf(s.w, "\n  %sRelated%s\n", colorDim, colorReset)
+		s.inRelated = true
+	}
+
+	// Note results that didn't come from the primary scraper
+	via := ""
+	if r.Source != "" && r.Source != domainr.SourceNamecheap {
+		via = fmt.Sprintf("  %s(via %s)%s", colorDim, r.Source, colorReset)
+	}
+	if r.OverBudget {
+		via += fmt.Sprintf("  %s(over budget)%s", colorRed, colorReset)
+	}
+	switch {
+	case r.Stale:
+		via += fmt.Sprintf("  %s(stale, cached %s ago, refreshing)%s", colorYellow, formatAge(time.Since(r.CheckedAt)), colorReset)
+	case r.Cached:
+		via += fmt.Sprintf("  %s(cached %s ago)%s", colorDim, formatAge(time.Since(r.CheckedAt)), colorReset)
+	}
+
+	price := r.Price
+	if r.RenewalPrice != "" {
+		price += fmt.Sprintf(" (renews %s)", r.RenewalPrice)
+	}
+
+	padded := r.Domain
+	if n := utf8.RuneCountInString(r.Domain); n < s.width {
+		padded += strings.Repeat(" ", s.width-n)
+	}
+
+	var err error
+	switch r.Status {
+	case domainr.StatusAvailable:
+		_, err = fmt.Fprintf(s.w, "  %s%s%s  %s%s Available %s  %s%s%s%s\n",
+			colorBold, padded, colorReset,
+			colorGreen, colorBold, colorReset,
+			colorDim, price, colorReset, via)
+	case domainr.StatusPremium:
+		if price != "" {
+			price = fmt.Sprintf("  %s%s%s", colorDim, price, colorReset)
+		}
+		_, err = fmt.Fprintf(s.w, "  %s%s%s  %s%s Premium   %s%s%s\n",
+			colorBold, padded, colorReset,
+			colorPurple, colorBold, colorReset, price, via)
+	case domainr.StatusMakeOffer:
+		if price != "" {
+			price = fmt.Sprintf("  %s%s%s", colorDim, price, colorReset)
+		}
+		_, err = fmt.Fprintf(s.w, "  %s%s%s  %s%s Make offer%s%s%s\n",
+			colorBold, padded, colorReset,
+			colorPurple, colorBold, colorReset, price, via)
+	case domainr.StatusTaken:
+		_, err = fmt.Fprintf(s.w, "  %s%s%s  %s%s Taken     %s%s\n",
+			colorBold, padded, colorReset,
+			colorRed, colorBold, colorReset, via)
+	case domainr.StatusReserved:
+		_, err = fmt.Fprintf(s.w, "  %s%s%s  %s%s Reserved  %s%s\n",
+			colorBold, padded, colorReset,
+			colorRed, colorBold, colorReset, via)
+	case domainr.StatusRestricted:
+		_, err = fmt.Fprintf(s.w, "  %s%s%s  %s%s Restricted%s%s\n",
+			colorBold, padded, colorReset,
+			colorYellow, colorBold, colorReset, via)
+	default:
+		reason := ""
+		if r.Reason != "" {
+			reason = fmt.Sprintf("  %s(%s)%s", colorDim, r.Reason, colorReset)
+		}
+		_, err = fmt.Fprintf(s.w, "  %s%s%s  %s%s Unknown   %s%s\n",
+			colorBold, padded, colorReset,
+			colorYellow, colorBold, colorReset,
+			reason)
+	}
+	if err == nil && len(r.Prices) > 0 {
+		_, err = fmt.Fprintf(s.w, "  %s  %s%s%s\n",
+			strings.Repeat(" ", s.width), colorDim, describePrices(r.Prices), colorReset)
+	}
+	for _, l := range r.Links {
+		if err != nil {
+			break
+		}
+		_, err = fmt.Fprintf(s.w, "  %s  %s%-10s %s%s\n",
+			strings.Repeat(" ", s.width), colorDim, l.Name, l.URL, colorReset)
+	}
+	if err == nil && r.Rationale != "" {
+		_, err = fmt.Fprintf(s.w, "  %s  %s%s%s\n",
+			strings.Repeat(" ", s.width), colorDim, r.Rationale, colorReset)
+	}
+	if err == nil && r.Registration != nil {
+		_, err = fmt.Fprintf(s.w, "  %s  %s%s%s\n",
+			strings.Repeat(" ", s.width), colorDim, describeRegistration(*r.Registration), colorReset)
+	}
+	if err == nil && len(r.Socials) > 0 {
+		_, err = fmt.Fprintf(s.w, "  %s  %s\n", strings.Repeat(" ", s.width), describeSocials(r.Socials))
+	}
+	for _, l := range r.Listings {
+		if err != nil {
+			break
+		}
+		_, err = fmt.Fprintf(s.w, "  %s  %s%-10s %s%s  %s%s\n",
+			strings.Repeat(" ", s.width), colorPurple, l.Marketplace, describeListing(l), colorReset, colorDim+l.URL, colorReset)
+	}
+	if err == nil && r.TLDInfo != nil {
+		_, err = fmt.Fprintf(s.w, "  %s  %s%s%s\n",
+			strings.Repeat(" ", s.width), colorDim, describeTLD(*r.TLDInfo), colorReset)
+	}
+	return err
+}
+
+// describePrices lists registrar prices on one line, marking the cheapest.
+func describePrices(prices []domainr.RegistrarPrice) string {
+	cheapest, best := -1, 0.0
+	for i, p := range prices {
+		if amount, ok := domainr.ParsePrice(p.Price); ok && (cheapest < 0 || amount < best) {
+			cheapest, best = i, amount
+		}
+	}
+
+	parts := make([]string, len(prices))
+	for i, p := range prices {
+		parts[i] = fmt.Sprintf("%s %s", p.Registrar, p.Price)
+		if i == cheapest && len(prices) > 1 {
+			parts[i] += " (cheapest)"
+		}
+	}
+	return strings.Join(parts, " · ")
+}
+
+// formatAge renders a duration coarsely, e.g. "12m" or "3h".
+func formatAge(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return "<1m"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	default:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	}
+}
+
+// describeRegistration summarizes who a domain is registered with and when it
+// expires, noting expiry dates close enough that the domain might drop soon.
+func describeRegistration(reg domainr.Registration) string {
+	var parts []string
+	if reg.Registrar != "" {
+		parts = append(parts, reg.Registrar)
+	}
+	if reg.Created != nil {
+		parts = append(parts, "registered "+reg.Created.Format(time.DateOnly))
+	}
+	if reg.Expires != nil {
+		expires := "expires " + reg.Expires.Format(time.DateOnly)
+		if days := int(time.Until(*reg.Expires).Hours() / 24); days < 0 {
+			expires += " (expired)"
+		} else if days <= 90 {
+			expires += fmt.Sprintf(" (in %d days)", days)
+		}
+		parts = append(parts, expires)
+	}
+	if len(parts) == 0 {
+		return "no registration details published"
+	}
+	return strings.Join(parts, " · ")
+}
+
+// describeSocials lists a name's username availability on one line, e.g.
+// "@mybrand  github ✓  x ✗  instagram ?".
+func describeSocials(handles []domainr.SocialHandle) string {
+	parts := []string{fmt.Sprintf("%s@%s%s", colorDim, handles[0].Handle, colorReset)}
+	for _, h := range handles {
+		mark := colorYellow + "?"
+		switch h.Status {
+		case domainr.StatusAvailable:
+			mark = colorGreen + "✓"
+		case domainr.StatusTaken:
+			mark = colorRed + "✗"
+		}
+		parts = append(parts, fmt.Sprintf("%s%s %s%s", colorDim, h.Platform, mark, colorReset))
+	}
+	return strings.Join(parts, "  ")
+}
+
+// describeListing says how a listed domain can be bought.
+func describeListing(l domainr.Listing) string {
+	switch {
+	case l.BuyNow:
+		return "buy now " + l.Price
+	case l.Price != "":
+		return "make offer, minimum " + l.Price
+	default:
+		return "make offer"
+	}
+}
+
+// describeTLD summarizes TLD metadata on one line.
+func describeTLD(t domainr.TLDInfo) string {
+	parts := []string{"." + t.TLD, t.Registry, fmt.Sprintf("since %d", t.Launched)}
+	if t.Privacy {
+		parts = append(parts, "WHOIS privacy")
+	} else {
+		parts = append(parts, "no WHOIS privacy")
+	}
+	if t.DNSSEC {
+		parts = append(parts, "DNSSEC")
+	} else {
+		parts = append(parts, "no DNSSEC")
+	}
+	return strings.Join(parts, " · ")
+}
+
+func (s *textSink) Close() error {
+	if !s.started {
+		fmt.Fprintln(s.w)
+	}
+	_, err := fmt.Fprintln(s.w)
+	return err
+}
+
+// jsonSink writes results as a JSON array, one element at a time.
+type jsonSink struct {
+	w io.Writer
+	n int
+}
+
+func (s *jsonSink) Write(r domainr.DomainResult) error {
+	// Results built outside the checker, like brute force ones, lack them
+	if r.ASCII == "" {
+		r.ASCII, r.Unicode = domainr.ToASCII(r.Domain), domainr.ToUnicode(r.Domain)
+	}
+	data, err := json.MarshalIndent(r, "  ", "  ")
+	if err != nil {
+		return err
+	}
+	sep := ",\n  "
+	if s.n == 0 {
+		sep = "[\n  "
+	}
+	s.n++
+	_, err = fmt.Fprintf(s.w, "%s%s", sep, data)
+	return err
+}
+
+func (s *jsonSink) Close() error {
+	if s.n == 0 {
+		_, err := fmt.Fprintln(s.w, "[]")
+		return err
+	}
+	_, err := fmt.Fprintln(s.w, "\n]")
+	return err
+}
+
+// scriptFilterItem is one row in an Alfred/Raycast script filter.
+type scriptFilterItem struct {
+	UID      string `json:"uid"`
+	Title    string `json:"title"`
+	Subtitle string `json:"subtitle"`
+	Arg      string `json:"arg"`
+	Valid    bool   `json:"valid"`
+}
+
+// scriptFilterSink writes results as script filter JSON for launcher
+// extensions. Actioning an item opens its purchase page, or the site itself
+// for taken domains.
+type scriptFilterSink struct {
+	w     io.Writer
+	items []scriptFilterItem
+}
+
+func (s *scriptFilterSink) Write(r domainr.DomainResult) error {
+	item := scriptFilterItem{UID: r.Domain, Title: r.Domain, Arg: domainr.PurchaseURL(r.Domain), Valid: true}
+	switch r.Status {
+	case domainr.StatusAvailable:
+		item.Subtitle = "Available"
+	case domainr.StatusPremium:
+		item.Subtitle = "Premium"
+	case domainr.StatusRestricted:
+		item.Subtitle = "Restricted (eligibility requirements apply)"
+	case domainr.StatusMakeOffer:
+		item.Subtitle = "Make offer"
+	case domainr.StatusTaken, domainr.StatusReserved:
+		item.Subtitle = "Taken"
+		if r.Status == domainr.StatusReserved {
+			item.Subtitle = "Reserved by the registry"
+		}
+		item.Arg = "https://" + r.Domain
+	default:
+		item.Subtitle = "Unknown"
+		if r.Reason != "" {
+			item.Subtitle += ": " + r.Reason
+		}
+		item.Valid = false
+	}
+	if r.Price != "" && r.Status != domainr.StatusTaken && r.Status != domainr.StatusReserved {
+		item.Subtitle += " · " + r.Price
+	}
+	s.items = append(s.items, item)
+	return nil
+}
+
+func (s *scriptFilterSink) Close() error {
+	items := s.items
+	if items == nil {
+		items = []scriptFilterItem{}
+	}
+	return json.NewEncoder(s.w).Encode(map[string]any{"items": items})
+}
+
+// csvSink writes results as CSV rows under a header row.
+type csvSink struct {
+	c  io.WriteCloser
+	w  *csv.Writer
+	ok bool
+}
+
+func newCSVSink(w io.WriteCloser) *csvSink {
+	return &csvSink{c: w, w: csv.NewWriter(w)}
+}
+
+func (s *csvSink) Write(r domainr.DomainResult) error {
+	if !s.ok {
+		s.ok = true
+		if err := s.w.Write([]string{"Domain", "Status", "Price", "Renewal Price", "Reason", "Timestamp", "Amount", "Currency"}); err != nil {
+			return err
+		}
+	}
+	amount := ""
+	if r.Currency != "" || r.PriceAmount != 0 {
+		amount = strconv.FormatFloat(r.PriceAmount, 'f', -1, 64)
+	}
+	err := s.w.Write([]string{r.Domain, r.Status.String(), r.Price, r.RenewalPrice, r.Reason, r.CheckedAt.Format(time.RFC3339), amount, r.Currency})
+	// Flush per row so streamed runs leave a usable file if interrupted
+	s.w.Flush()
+	return errors.Join(err, s.w.Error())
+}
+
+func (s *csvSink) Close() error {
+	s.w.Flush()
+	return errors.Join(s.w.Error(), s.c.Close())
+}
+
+// multiSink writes every result to several sinks.
+type multiSink []resultSink
+
+func (m multiSink) Write(r domainr.DomainResult) error {
+	var errs []error
+	for _, s := range m {
+		errs = append(errs, s.Write(r))
+	}
+	return errors.Join(errs...)
+}
+
+func (m multiSink) Close() error {
+	var errs []error
+	for _, s := range m {
+		errs = append(errs, s.Close())
+	}
+	return errors.Join(errs...)
+}
+
+// tldInfoSink attaches TLD metadata to results before passing them on.
+type tldInfoSink struct{ resultSink }
+
+func (s tldInfoSink) Write(r domainr.DomainResult) error {
+	if info, ok := domainr.LookupTLD(r.Domain); ok {
+		r.TLDInfo = &info
+	}
+	return s.resultSink.Write(r)
+}
+
+// rationaleSink attaches the reason each domain was generated.
+type rationaleSink struct {
+	resultSink
+	why map[string]string
+}
+
+func (s rationaleSink) Write(r domainr.DomainResult) error {
+	if why, ok := s.why[strings.ToLower(r.Domain)]; ok {
+		r.Rationale = why
+	}
+	return s.resultSink.Write(r)
+}
+
+// idnSink rewrites each result's domain to the configured display form.
+type idnSink struct {
+	resultSink
+	form string
+}
+
+func (s idnSink) Write(r domainr.DomainResult) error {
+	switch s.form {
+	case "ascii":
+		r.Domain = domainr.ToASCII(r.Domain)
+	case "unicode":
+		r.Domain = domainr.ToUnicode(r.Domain)
+	}
+	return s.resultSink.Write(r)
+}
+
+// compareSink adds registrar prices to available results. The checking
+// backend's price comes from the result itself.
+type compareSink struct {
+	resultSink
+	porkbun domainr.PriceTable
+	godaddy map[string]string
+}
+
+func (s compareSink) Write(r domainr.DomainResult) error {
+	if r.Status == domainr.StatusAvailable {
+		source := cmp.Or(r.Source, domainr.SourceNamecheap)
+		if r.Price != "" {
+			r.Prices = append(r.Prices, domainr.RegistrarPrice{Registrar: source, Price: r.Price})
+		}
+		if price, ok := s.porkbun.Lookup(r.Domain); ok && source != domainr.SourcePorkbun {
+			r.Prices = append(r.Prices, domainr.RegistrarPrice{Registrar: domainr.SourcePorkbun, Price: price})
+		}
+		if price, ok := s.godaddy[strings.ToLower(domainr.ToASCII(r.Domain))]; ok && source != domainr.SourceGoDaddy {
+			r.Prices = append(r.Prices, domainr.RegistrarPrice{Registrar: domainr.SourceGoDaddy, Price: price})
+		}
+	}
+	return s.resultSink.Write(r)
+}
+
+type nopCloser struct{ io.Writer }
+
+func (nopCloser) Close() error { return nil }
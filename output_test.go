@@ -0,0 +1,87 @@
+package main
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/jpoz/domainr/pkg/domainr"
+)
+
+// collectSink keeps the results written to it.
+type collectSink struct{ results []domainr.DomainResult }
+
+func (s *collectSink) Write(r domainr.DomainResult) error {
+	s.results = append(s.results, r)
+	return nil
+}
+
+func (s *collectSink) Close() error { return nil }
+
+func TestCompareSink(t *testing.T) {
+	tests := []struct {
+		name   string
+		result domainr.DomainResult
+		want   []domainr.RegistrarPrice
+	}{
+		{
+			name:   "namecheap with porkbun and godaddy",
+			result: domainr.DomainResult{Domain: "Example.IO", Status: domainr.StatusAvailable, Price: "$34.98", Source: domainr.SourceNamecheap},
+			want: []domainr.RegistrarPrice{
+				{Registrar: "namecheap", Price: "$34.98"},
+				{Registrar: "porkbun", Price: "$28.12"},
+				{Registrar: "godaddy", Price: "$44.99/yr"},
+			},
+		},
+		{
+			name:   "checked with godaddy",
+			result: domainr.DomainResult{Domain: "example.io", Status: domainr.StatusAvailable, Price: "$44.99/yr", Source: domainr.SourceGoDaddy},
+			want: []domainr.RegistrarPrice{
+				{Registrar: "godaddy", Price: "$44.99/yr"},
+				{Registrar: "porkbun", Price: "$28.12"},
+			},
+		},
+		{
+			name:   "no godaddy quote",
+			result: domainr.DomainResult{Domain: "other.io", Status: domainr.StatusAvailable, Price: "$34.98"},
+			want: []domainr.RegistrarPrice{
+				{Registrar: "namecheap", Price: "$34.98"},
+				{Registrar: "porkbun", Price: "$28.12"},
+			},
+		},
+		{
+			name:   "taken",
+			result: domainr.DomainResult{Domain: "example.io", Status: domainr.StatusTaken},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got collectSink
+			s := compareSink{&got, domainr.PriceTable{"io": "$28.12"}, map[string]string{"example.io": "$44.99/yr"}}
+			if err := s.Write(tt.result); err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(got.results[0].Prices, tt.want) {
+				t.Errorf("prices = %v, want %v", got.results[0].Prices, tt.want)
+			}
+		})
+	}
+}
+
+func TestCSVFlag(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    string
+		wantErr bool
+	}{
+		{value: "out.csv", want: "out.csv"},
+		{value: "-", want: "-"},
+		{value: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		var dest string
+		err := csvFlag{&dest}.Set(tt.value)
+		if (err != nil) != tt.wantErr || dest != tt.want {
+			t.Errorf("Set(%q) = %q, %v; want %q", tt.value, dest, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/jpoz/domainr/pkg/domainr"
+)
+
+// inquiryTemplate is a starting point for asking an owner to sell a domain.
+const inquiryTemplate = `Subject: Inquiry about %[1]s
+
+Hello,
+
+I came across %[1]s and wanted to ask whether you would consider selling
+it. If so, I'd be glad to hear what price you have in mind, or to make an
+offer.
+
+Thank you for your time.
+
+Best regards,
+`
+
+// printOutreach looks up published contacts for taken domains and prints
+// them with an inquiry template for each.
+func printOutreach(ctx context.Context, w io.Writer, taken []string) {
+	if len(taken) == 0 {
+		return
+	}
+
+	fmt.Fprintf(w, "  %sOutreach%s\n\n", colorBold, colorReset)
+	for _, d := range taken {
+		fmt.Fprintf(w, "  %s%s%s\n", colorBold, d, colorReset)
+
+		contacts, err := domainr.LookupContacts(ctx, d)
+		if ctx.Err() != nil {
+			return
+		}
+		switch {
+		case err != nil:
+			fmt.Fprintf(w, "    %scould not look up contacts: %v%s\n\n", colorDim, err, colorReset)
+			continue
+		case len(contacts) == 0:
+			fmt.Fprintf(w, "    %sno contact emails published%s\n\n", colorDim, colorReset)
+			continue
+		}
+
+		for _, c := range contacts {
+			fmt.Fprintf(w, "    %-15s %s  %s(%s)%s\n", c.Role, c.Email, colorDim, c.Source, colorReset)
+		}
+		if onlyRegistrar(contacts) {
+			fmt.Fprintf(w, "    %sOwner details are redacted; registrars will often forward a message on request%s\n", colorDim, colorReset)
+		}
+		fmt.Fprintln(w)
+		for _, line := range strings.Split(fmt.Sprintf(inquiryTemplate, d), "\n") {
+			fmt.Fprintf(w, "    %s\n", line)
+		}
+	}
+}
+
+// onlyRegistrar reports whether none of the contacts belong to the owner.
+func onlyRegistrar(contacts []domainr.Contact) bool {
+	for _, c := range contacts {
+		if c.Role != "abuse" && c.Role != "registrar" {
+			return false
+		}
+	}
+	return true
+}
+
+// isOwned reports whether r is a requested domain someone has registered, and
+// so whose owner might be asked to sell it.
+func isOwned(r domainr.DomainResult) bool {
+	return (r.Status == domainr.StatusTaken || r.Status == domainr.StatusMakeOffer) && !r.Related
+}
@@ -0,0 +1,200 @@
+package domainr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Listing is a domain's sale listing on an aftermarket marketplace.
+type Listing struct {
+	Marketplace string `json:"marketplace"`
+	URL         string `json:"url"`
+	// BuyNow is set when the domain can be bought outright at Price;
+	// otherwise the seller takes offers, and Price, if any, is the minimum.
+	BuyNow      bool    `json:"buy_now"`
+	Price       string  `json:"price,omitempty"`
+	PriceAmount float64 `json:"price_amount,omitempty"`
+	Currency    string  `json:"currency,omitempty"`
+}
+
+// marketplace is an aftermarket whose public landing pages are read for
+// listings.
+type marketplace struct {
+	name string
+	page func(domain string) string
+	// unlisted are phrases on a page for a domain that isn't for sale,
+	// lowercased
+	unlisted []string
+}
+
+var marketplaces = []marketplace{
+	{
+		name:     "Sedo",
+		page:     func(d string) string { return "https://sedo.com/search/details/?domain=" + url.QueryEscape(d) },
+		unlisted: []string{"not for sale", "is not listed", "no longer available"},
+	},
+	{
+		name:     "Afternic",
+		page:     func(d string) string { return "https://www.afternic.com/domain/" + url.PathEscape(d) },
+		unlisted: []string{"not for sale", "isn't for sale", "is not available"},
+	},
+	{
+		name:     "Dan",
+		page:     func(d string) string { return "https://dan.com/buy-domain/" + url.PathEscape(d) },
+		unlisted: []string{"not for sale", "isn't for sale", "page not found"},
+	},
+}
+
+var (
+	// Landing pages describe buy-now listings as schema.org offers
+	offerPriceRegex    = regexp.MustCompile(`"price"\s*:\s*"?([0-9][0-9,]*(?:\.[0-9]+)?)"?`)
+	offerCurrencyRegex = regexp.MustCompile(`"priceCurrency"\s*:\s*"([A-Z]{3})"`)
+	minOfferRegex      = regexp.MustCompile(`(?i)minimum offer[^0-9$€£]{0,40}([$€£]?\s?[0-9][0-9,]*(?:\.[0-9]+)?)`)
+)
+
+// LookupListings returns domain's listings on Sedo, Afternic and Dan, read
+// from their public landing pages. Marketplaces that couldn't be reached are
+// skipped; the error joins their failures only when none answered.
+func LookupListings(ctx context.Context, domain string) ([]Listing, error) {
+	domain = strings.ToLower(domain)
+	found := make([]*Listing, len(marketplaces))
+	errs := make([]error, len(marketplaces))
+	var wg sync.WaitGroup
+	for i, m := range marketplaces {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			found[i], errs[i] = m.lookup(ctx, domain)
+		}()
+	}
+	wg.Wait()
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	var listings []Listing
+	failed := 0
+	for i, l := range found {
+		if errs[i] != nil {
+			failed++
+		} else if l != nil {
+			listings = append(listings, *l)
+		}
+	}
+	if failed == len(marketplaces) {
+		return nil, errors.Join(errs...)
+	}
+	return listings, nil
+}
+
+// lookup reads m's landing page for domain, returning nil when the domain
+// isn't listed there.
+func (m marketplace) lookup(ctx context.Context, domain string) (*Listing, error) {
+	page := m.page(domain)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, page, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set("Accept", "text/html")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", strings.ToLower(m.name), err)
+	}
+	defer resp.Body.Close()
+	switch {
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone:
+		return nil, nil
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("%s: unexpected status %d", strings.ToLower(m.name), resp.StatusCode)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 4<<20))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", strings.ToLower(m.name), err)
+	}
+
+	l := parseListing(string(body), domain, m.unlisted)
+	if l != nil {
+		l.Marketplace = m.name
+		l.URL = page
+	}
+	return l, nil
+}
+
+// parseListing reads a listing from a marketplace landing page: a buy-now
+// price from its schema.org offer, or a make-offer listing with its minimum
+// offer, if stated. Pages that don't mention domain, or say it isn't for
+// sale, yield nil.
+func parseListing(page, domain string, unlisted []string) *Listing {
+	lower := strings.ToLower(page)
+	if !strings.Contains(lower, domain) {
+		return nil
+	}
+	for _, phrase := range unlisted {
+		if strings.Contains(lower, phrase) {
+			return nil
+		}
+	}
+
+	l := &Listing{}
+	if m := offerPriceRegex.FindStringSubmatch(page); m != nil && strings.Contains(lower, "buy now") {
+		amount, err := strconv.ParseFloat(strings.ReplaceAll(m[1], ",", ""), 64)
+		if err == nil && amount > 0 {
+			l.BuyNow = true
+			l.PriceAmount = amount
+			l.Currency = "USD"
+			if c := offerCurrencyRegex.FindStringSubmatch(page); c != nil {
+				l.Currency = c[1]
+			}
+			l.Price = listingPrice(amount, l.Currency)
+			return l
+		}
+	}
+	if !strings.Contains(lower, "make an offer") && !strings.Contains(lower, "make offer") {
+		return nil
+	}
+	if m := minOfferRegex.FindStringSubmatch(page); m != nil {
+		l.Price = strings.TrimSpace(m[1])
+		l.PriceAmount, _ = ParsePrice(l.Price)
+		l.Currency = ParseCurrency(l.Price)
+	}
+	return l
+}
+
+// listingPrice formats an asking price like the backends' prices.
+func listingPrice(amount float64, currency string) string {
+	formatted := strconv.FormatFloat(amount, 'f', -1, 64)
+	if amount != float64(int64(amount)) {
+		formatted = fmt.Sprintf("%.2f", amount)
+	}
+	if currency == "USD" {
+		return "$" + formatted
+	}
+	return formatted + " " + currency
+}
+
+// withListings wraps emit to attach aftermarket listings to taken and
+// premium results. Lookups that fail leave the result as it was.
+func withListings(ctx context.Context, emit func(DomainResult) error) func(DomainResult) error {
+	return func(r DomainResult) error {
+		switch r.Status {
+		case StatusTaken, StatusPremium, StatusMakeOffer:
+			if r.Related {
+				break
+			}
+			if listings, err := LookupListings(ctx, r.Domain); err == nil {
+				r.Listings = listings
+			}
+		}
+		return emit(r)
+	}
+}
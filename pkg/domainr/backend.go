@@ -0,0 +1,187 @@
+package domainr
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+)
+
+// Backend checks domains' availability. Check passes each requested domain's
+// result to emit in input order, optionally followed by related results, and
+// returns early with emit's error if it fails.
+type Backend interface {
+	Check(ctx context.Context, domains []string, opts Options, emit func(DomainResult) error) error
+}
+
+// BackendFunc adapts a function to the Backend interface.
+type BackendFunc func(ctx context.Context, domains []string, opts Options, emit func(DomainResult) error) error
+
+// Check calls f.
+func (f BackendFunc) Check(ctx context.Context, domains []string, opts Options, emit func(DomainResult) error) error {
+	return f(ctx, domains, opts, emit)
+}
+
+var (
+	backendsMu sync.RWMutex
+	backends   = map[string]Backend{
+		SourceNamecheap: BackendFunc(streamNamecheap),
+		SourceNamecheapAPI: BackendFunc(func(ctx context.Context, domains []string, opts Options, emit func(DomainResult) error) error {
+			cfg := opts.NamecheapAPI
+			if cfg == (NamecheapAPIConfig{}) {
+				cfg = NamecheapAPIConfigFromEnv()
+			}
+			return streamNamecheapAPI(ctx, domains, cfg, emit)
+		}),
+		SourcePorkbun: BackendFunc(func(ctx context.Context, domains []string, opts Options, emit func(DomainResult) error) error {
+			cfg := opts.Porkbun
+			if cfg == (PorkbunConfig{}) {
+				cfg = PorkbunConfigFromEnv()
+			}
+			return streamPorkbun(ctx, domains, cfg, emit)
+		}),
+		SourceGoDaddy: BackendFunc(func(ctx context.Context, domains []string, opts Options, emit func(DomainResult) error) error {
+			cfg := opts.GoDaddy
+			if cfg == (GoDaddyConfig{}) {
+				cfg = GoDaddyConfigFromEnv()
+			}
+			return streamGoDaddy(ctx, domains, cfg, emit)
+		}),
+		SourceRDAP: BackendFunc(func(ctx context.Context, domains []string, opts Options, emit func(DomainResult) error) error {
+			return streamRDAP(ctx, domains, emit)
+		}),
+	}
+)
+
+// RegisterBackend makes b selectable by name in Options.Backend and
+// Options.Fallbacks, replacing any backend already registered under it.
+func RegisterBackend(name string, b Backend) {
+	backendsMu.Lock()
+	defer backendsMu.Unlock()
+	backends[name] = b
+}
+
+// Backends returns the names of all registered backends, sorted.
+func Backends() []string {
+	backendsMu.RLock()
+	defer backendsMu.RUnlock()
+	names := make([]string, 0, len(backends))
+	for name := range backends {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+func lookupBackend(name string) (Backend, error) {
+	if name == "" {
+		name = SourceNamecheap
+	}
+	backendsMu.RLock()
+	defer backendsMu.RUnlock()
+	b, ok := backends[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown backend %q", name)
+	}
+	return b, nil
+}
+
+// streamChain checks domains with the first backend in chain. Each domain it
+// can't check, because its result is unknown or the backend failed before
+// reaching it, is handed to the rest of the chain in turn.
+//
+// Each backend has a circuit breaker: once it fails Options.BreakerThreshold
+// checks in a row, its run is stopped and, for Options.BreakerCooldown, its
+// domains go straight to the fallbacks, after which a single domain probes
+// whether it works again.
+func streamChain(ctx context.Context, chain []string, domains []string, opts Options, emit func(DomainResult) error) error {
+	if threshold, cooldown := breakerSettings(opts); threshold > 0 && len(chain) > 1 {
+		switch breakerFor(chain[0]).acquire(cooldown) {
+		case breakerOpen:
+			return streamChain(ctx, chain[1:], domains, opts, emit)
+		case breakerHalfOpen:
+			err := runChain(ctx, chain, domains[:1], opts, emit)
+			// A probe cut short before its result was recorded must not
+			// keep the breaker open for good
+			breakerFor(chain[0]).release()
+			if err != nil {
+				return err
+			}
+			if len(domains) == 1 {
+				return nil
+			}
+			return streamChain(ctx, chain, domains[1:], opts, emit)
+		}
+	}
+	return runChain(ctx, chain, domains, opts, emit)
+}
+
+// runChain is streamChain past the circuit breaker check: it runs the first
+// backend in chain, recording its results with its breaker.
+func runChain(ctx context.Context, chain []string, domains []string, opts Options, emit func(DomainResult) error) error {
+	backend, err := lookupBackend(chain[0])
+	if err != nil {
+		return err
+	}
+	fallbacks := chain[1:]
+	threshold, cooldown := breakerSettings(opts)
+	breaker := breakerFor(chain[0])
+
+	// The breaker opening stops only this backend's run
+	run, stop := context.WithCancelCause(ctx)
+	defer stop(nil)
+
+	// done counts requested results emitted, which arrive in input order
+	done := 0
+	var emitErr error
+	err = backend.Check(run, domains, opts, func(r DomainResult) error {
+		if !r.Related {
+			done++
+			// Results a backend covered with its own fallback, like
+			// Namecheap's WHOIS lookups, count as failures too
+			ok := r.Status != StatusUnknown && (r.Source == "" || r.Source == chain[0])
+			if threshold > 0 && len(fallbacks) > 0 && breaker.record(ok, threshold) {
+				opts.logf("%s keeps failing, checking with %s instead for the next %v\n", chain[0], fallbacks[0], cooldown)
+				stop(errBreakerOpen)
+			}
+			if r.Status == StatusUnknown && len(fallbacks) > 0 {
+				if fb, ok := checkOne(ctx, fallbacks, r.Domain, opts); ok {
+					r = fb
+				}
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+			}
+		}
+		emitErr = emit(r)
+		return emitErr
+	})
+	if ctx.Err() != nil || emitErr != nil {
+		return err
+	}
+	if context.Cause(run) == errBreakerOpen {
+		err = nil
+	} else if err == nil {
+		return nil
+	} else if threshold > 0 && len(fallbacks) > 0 {
+		breaker.record(false, threshold)
+	}
+	if len(fallbacks) == 0 || done >= len(domains) {
+		return err
+	}
+	return streamChain(ctx, fallbacks, domains[done:], opts, emit)
+}
+
+// checkOne checks a single domain with chain, reporting whether it found a
+// known status.
+func checkOne(ctx context.Context, chain []string, domain string, opts Options) (DomainResult, bool) {
+	var result DomainResult
+	found := false
+	streamChain(ctx, chain, []string{domain}, opts, func(r DomainResult) error {
+		if !r.Related {
+			result, found = r, r.Status != StatusUnknown
+		}
+		return nil
+	})
+	return result, found
+}
@@ -0,0 +1,97 @@
+package domainr
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	baseRequestDelay = 1500 * time.Millisecond
+	maxRequestDelay  = 30 * time.Second
+
+	// blockWindow is how long a Cloudflare block keeps slowing down requests,
+	// including across restarts.
+	blockWindow = 30 * time.Minute
+)
+
+// backoffState tracks recent Cloudflare blocks and is persisted between runs
+// so a restarted process doesn't immediately resume scraping at full speed.
+type backoffState struct {
+	Blocks []time.Time   `json:"blocks"`
+	Delay  time.Duration `json:"delay"`
+
+	path string
+}
+
+// loadBackoff reads the state persisted in dir, next to the result cache, or
+// in domainr under the user cache directory when dir is empty. A missing or
+// unreadable file yields a fresh state; persistence is best-effort.
+func loadBackoff(dir string) *backoffState {
+	b := &backoffState{}
+	if dir = cacheDir(dir); dir != "" {
+		b.path = filepath.Join(dir, "backoff.json")
+		if data, err := os.ReadFile(b.path); err == nil {
+			json.Unmarshal(data, b)
+		}
+	}
+	b.update(time.Now())
+	return b
+}
+
+// update drops blocks outside the window and recomputes the request delay,
+// doubling it for each recent block.
+func (b *backoffState) update(now time.Time) {
+	recent := b.Blocks[:0]
+	for _, t := range b.Blocks {
+		if now.Sub(t) < blockWindow {
+			recent = append(recent, t)
+		}
+	}
+	b.Blocks = recent
+
+	b.Delay = baseRequestDelay
+	for range b.Blocks {
+		b.Delay *= 2
+		if b.Delay >= maxRequestDelay {
+			b.Delay = maxRequestDelay
+			break
+		}
+	}
+}
+
+// InitialWait returns how long to wait before the first request so that the
+// current delay has passed since the most recent block.
+func (b *backoffState) InitialWait() time.Duration {
+	if len(b.Blocks) == 0 {
+		return 0
+	}
+	wait := time.Until(b.Blocks[len(b.Blocks)-1].Add(b.Delay))
+	if wait < 0 {
+		return 0
+	}
+	return wait
+}
+
+// RecordBlock notes a Cloudflare block, raises the delay, and saves the state.
+func (b *backoffState) RecordBlock() {
+	now := time.Now()
+	b.Blocks = append(b.Blocks, now)
+	b.update(now)
+	b.save()
+}
+
+func (b *backoffState) save() {
+	if b.path == "" {
+		return
+	}
+	data, err := json.Marshal(b)
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(b.path), 0o755); err != nil {
+		return
+	}
+	os.WriteFile(b.path, data, 0o644)
+}
@@ -0,0 +1,21 @@
+package domainr
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestBackoffKeptInCacheDir(t *testing.T) {
+	dir := t.TempDir()
+	loadBackoff(dir).RecordBlock()
+	if _, err := os.Stat(filepath.Join(dir, "backoff.json")); err != nil {
+		t.Fatalf("backoff state not saved in the cache dir: %v", err)
+	}
+	if b := loadBackoff(dir); len(b.Blocks) != 1 || b.Delay != 2*baseRequestDelay {
+		t.Errorf("reloaded state = %+v, want one block", b)
+	}
+	if b := loadBackoff(t.TempDir()); len(b.Blocks) != 0 {
+		t.Errorf("another cache dir shares the block: %+v", b)
+	}
+}
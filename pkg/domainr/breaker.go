@@ -0,0 +1,121 @@
+package domainr
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// Defaults for Options.BreakerThreshold and Options.BreakerCooldown.
+const (
+	DefaultBreakerThreshold = 5
+	DefaultBreakerCooldown  = time.Minute
+)
+
+// errBreakerOpen cancels a backend's run once its circuit breaker opens.
+var errBreakerOpen = errors.New("circuit breaker open")
+
+// breakerState is a circuit breaker's position.
+type breakerState int
+
+const (
+	// breakerClosed lets every check through
+	breakerClosed breakerState = iota
+	// breakerOpen fails checks over to the fallbacks until the cooldown
+	// ends
+	breakerOpen
+	// breakerHalfOpen lets one probe through to decide whether to close
+	// again
+	breakerHalfOpen
+)
+
+// circuitBreaker tracks one backend's consecutive failures. Breakers are
+// shared by all runs in the process, so a daemon serving many checks stops
+// sending them to a backend that keeps failing.
+type circuitBreaker struct {
+	mu       sync.Mutex
+	state    breakerState
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+var (
+	breakersMu sync.Mutex
+	breakers   = make(map[string]*circuitBreaker)
+)
+
+func breakerFor(backend string) *circuitBreaker {
+	breakersMu.Lock()
+	defer breakersMu.Unlock()
+	b, ok := breakers[backend]
+	if !ok {
+		b = &circuitBreaker{}
+		breakers[backend] = b
+	}
+	return b
+}
+
+// acquire returns how a run may use the backend: breakerClosed to check all
+// its domains, breakerHalfOpen to check just one as a probe, or breakerOpen
+// to skip it. An open breaker turns half-open once cooldown has passed, and
+// only one probe runs at a time.
+func (b *circuitBreaker) acquire(cooldown time.Duration) breakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state == breakerOpen && time.Since(b.openedAt) >= cooldown {
+		b.state = breakerHalfOpen
+	}
+	if b.state == breakerHalfOpen {
+		if b.probing {
+			return breakerOpen
+		}
+		b.probing = true
+	}
+	return b.state
+}
+
+// record counts a result, reporting whether it opened the breaker: after
+// threshold consecutive failures, or a failed probe. A success closes it.
+func (b *circuitBreaker) record(ok bool, threshold int) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+	if ok {
+		b.state, b.failures = breakerClosed, 0
+		return false
+	}
+	b.failures++
+	if b.state == breakerOpen || (b.state == breakerClosed && b.failures < threshold) {
+		return false
+	}
+	b.state, b.openedAt = breakerOpen, time.Now()
+	return true
+}
+
+// release ends a probe that recorded no result, for example because the run
+// was cancelled or its output failed, so that a later run probes again.
+func (b *circuitBreaker) release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state == breakerHalfOpen {
+		b.probing = false
+	}
+}
+
+// breakerSettings returns the threshold and cooldown opts configure, with a
+// zero threshold meaning the breaker is disabled.
+func breakerSettings(opts Options) (int, time.Duration) {
+	threshold := opts.BreakerThreshold
+	switch {
+	case threshold < 0:
+		threshold = 0
+	case threshold == 0:
+		threshold = DefaultBreakerThreshold
+	}
+	cooldown := opts.BreakerCooldown
+	if cooldown <= 0 {
+		cooldown = DefaultBreakerCooldown
+	}
+	return threshold, cooldown
+}
@@ -0,0 +1,66 @@
+package domainr
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestBreakerProbeReleasedOnCancel(t *testing.T) {
+	var healthy atomic.Bool
+	RegisterBackend("test-flaky", BackendFunc(func(ctx context.Context, domains []string, opts Options, emit func(DomainResult) error) error {
+		for _, d := range domains {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			r := DomainResult{Domain: d, Source: "test-flaky"}
+			if healthy.Load() {
+				r.Status = StatusAvailable
+			}
+			if err := emit(r); err != nil {
+				return err
+			}
+		}
+		return nil
+	}))
+	RegisterBackend("test-steady", BackendFunc(func(ctx context.Context, domains []string, opts Options, emit func(DomainResult) error) error {
+		for _, d := range domains {
+			if err := emit(DomainResult{Domain: d, Status: StatusTaken, Source: "test-steady"}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}))
+
+	opts := Options{BreakerThreshold: 2, BreakerCooldown: time.Millisecond}
+	chain := []string{"test-flaky", "test-steady"}
+	sources := func(ctx context.Context, domains ...string) []string {
+		var got []string
+		streamChain(ctx, chain, domains, opts, func(r DomainResult) error {
+			got = append(got, r.Source)
+			return nil
+		})
+		return got
+	}
+
+	// Two failures open the breaker
+	sources(context.Background(), "a.test", "b.test", "c.test")
+	if state := breakerFor("test-flaky").acquire(time.Hour); state != breakerOpen {
+		t.Fatalf("breaker state after failures = %v, want open", state)
+	}
+
+	// A probe cancelled before its backend answers records nothing
+	time.Sleep(5 * time.Millisecond)
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	sources(cancelled, "d.test")
+
+	// The next run probes again, and a healthy backend closes the breaker
+	time.Sleep(5 * time.Millisecond)
+	healthy.Store(true)
+	got := sources(context.Background(), "e.test", "f.test")
+	if len(got) != 2 || got[0] != "test-flaky" || got[1] != "test-flaky" {
+		t.Errorf("sources after recovery = %v, want the recovered backend for both", got)
+	}
+}
@@ -0,0 +1,119 @@
+package domainr
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// resultCache holds recent results keyed by lowercased domain and is
+// persisted between runs so repeated checks of the same list don't scrape
+// again.
+type resultCache struct {
+	Results map[string]DomainResult `json:"results"`
+
+	path string
+}
+
+// cacheDir returns dir, or domainr under the user cache directory when dir
+// is empty, or "" when there is none.
+func cacheDir(dir string) string {
+	if dir == "" {
+		if base, err := os.UserCacheDir(); err == nil {
+			dir = filepath.Join(base, "domainr")
+		}
+	}
+	return dir
+}
+
+// loadCache reads the cache persisted in dir, or in domainr under the user
+// cache directory when dir is empty. Like the backoff state, persistence is
+// best-effort: a missing or unreadable file yields an empty cache.
+func loadCache(dir string) *resultCache {
+	c := &resultCache{}
+	if dir = cacheDir(dir); dir != "" {
+		c.path = filepath.Join(dir, "results.json")
+		if data, err := os.ReadFile(c.path); err == nil {
+			json.Unmarshal(data, c)
+		}
+	}
+	if c.Results == nil {
+		c.Results = make(map[string]DomainResult)
+	}
+	return c
+}
+
+// get returns the cached result for domain if it was checked within ttl.
+func (c *resultCache) get(domain string, ttl time.Duration) (DomainResult, bool) {
+	r, ok := c.Results[strings.ToLower(domain)]
+	if !ok || time.Since(r.CheckedAt) > ttl {
+		return DomainResult{}, false
+	}
+	r.Domain = domain
+	r.Cached = true
+	return r, true
+}
+
+// put caches a definitive result; failed checks are always retried. It does
+// nothing on a nil cache, so callers needn't check whether caching is on.
+func (c *resultCache) put(r DomainResult) {
+	if c == nil || r.Status == StatusUnknown || r.Related {
+		return
+	}
+	c.Results[strings.ToLower(r.Domain)] = r
+}
+
+// save writes the cache, first dropping entries older than ttl. Other runs
+// may have saved results since this one loaded the cache, so under a lock it
+// merges them in, keeping the newer result for each domain, and replaces the
+// file by renaming a complete copy over it.
+func (c *resultCache) save(ttl time.Duration) {
+	if c.path == "" {
+		return
+	}
+	dir := filepath.Dir(c.path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return
+	}
+	unlock, err := lockFile(c.path + ".lock")
+	if err != nil {
+		return
+	}
+	defer unlock()
+
+	var saved resultCache
+	if data, err := os.ReadFile(c.path); err == nil {
+		json.Unmarshal(data, &saved)
+	}
+	for d, r := range saved.Results {
+		if cur, ok := c.Results[d]; !ok || r.CheckedAt.After(cur.CheckedAt) {
+			c.Results[d] = r
+		}
+	}
+	for d, r := range c.Results {
+		if time.Since(r.CheckedAt) > ttl {
+			delete(c.Results, d)
+		}
+	}
+	data, err := json.Marshal(c)
+	if err != nil {
+		return
+	}
+	tmp, err := os.CreateTemp(dir, "results-*.json")
+	if err != nil {
+		return
+	}
+	tmp.Chmod(0o644)
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), c.path)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+	}
+}
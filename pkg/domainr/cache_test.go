@@ -0,0 +1,31 @@
+package domainr
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCacheSaveMerges(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+
+	// Two runs load the cache before either saves
+	first, second := loadCache(dir), loadCache(dir)
+	first.put(DomainResult{Domain: "a.com", Status: StatusTaken, CheckedAt: now.Add(-time.Minute)})
+	first.put(DomainResult{Domain: "b.com", Status: StatusTaken, CheckedAt: now.Add(-time.Minute)})
+	second.put(DomainResult{Domain: "B.com", Status: StatusAvailable, CheckedAt: now})
+	second.put(DomainResult{Domain: "c.com", Status: StatusAvailable, CheckedAt: now.Add(-2 * time.Hour)})
+	first.save(time.Hour)
+	second.save(time.Hour)
+
+	got := loadCache(dir).Results
+	want := map[string]DomainStatus{"a.com": StatusTaken, "b.com": StatusAvailable}
+	if len(got) != len(want) {
+		t.Fatalf("cached %d results, want %d: %+v", len(got), len(want), got)
+	}
+	for d, status := range want {
+		if got[d].Status != status {
+			t.Errorf("%s cached as %v, want %v", d, got[d].Status, status)
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package domainr
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// Contact is an email address published for a domain.
+type Contact struct {
+	// Role is who the address belongs to, e.g. "registrant" or "abuse".
+	// Abuse contacts belong to the registrar, not the domain's owner.
+	Role   string `json:"role"`
+	Email  string `json:"email"`
+	Source string `json:"source"`
+}
+
+var emailRegex = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)
+
+// LookupContacts returns the contact emails published for domain in its RDAP
+// and WHOIS records. Most registrant details are redacted, in which case
+// usually only the registrar's abuse contact is found.
+func LookupContacts(ctx context.Context, domain string) ([]Contact, error) {
+	domain = strings.ToLower(domain)
+
+	rdap, rdapErr := rdapContacts(ctx, domain)
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	whois, whoisErr := whoisContacts(ctx, domain)
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	if rdapErr != nil && whoisErr != nil {
+		return nil, errors.Join(rdapErr, whoisErr)
+	}
+
+	var contacts []Contact
+	seen := make(map[string]bool)
+	for _, c := range append(rdap, whois...) {
+		key := c.Role + " " + strings.ToLower(c.Email)
+		if !seen[key] {
+			seen[key] = true
+			contacts = append(contacts, c)
+		}
+	}
+	return contacts, nil
+}
+
+// rdapEntity is a contact in an RDAP domain object. Entities nest, e.g. a
+// registrar's abuse contact inside the registrar.
+type rdapEntity struct {
+	Roles    []string     `json:"roles"`
+	VCard    []any        `json:"vcardArray"`
+	Entities []rdapEntity `json:"entities"`
+}
+
+func rdapContacts(ctx context.Context, domain string) ([]Contact, error) {
+	body, code, err := rdapQuery(ctx, domain)
+	if err != nil {
+		return nil, err
+	}
+	if code != http.StatusOK {
+		return nil, fmt.Errorf("rdap: unexpected status %d", code)
+	}
+
+	var obj struct {
+		Entities []rdapEntity `json:"entities"`
+	}
+	if err := json.Unmarshal(body, &obj); err != nil {
+		return nil, fmt.Errorf("rdap: %w", err)
+	}
+
+	var contacts []Contact
+	var walk func([]rdapEntity)
+	walk = func(entities []rdapEntity) {
+		for _, e := range entities {
+			for _, email := range vcardEmails(e.VCard) {
+				for _, role := range e.Roles {
+					contacts = append(contacts, Contact{Role: role, Email: email, Source: SourceRDAP})
+				}
+			}
+			walk(e.Entities)
+		}
+	}
+	walk(obj.Entities)
+	return contacts, nil
+}
+
+// vcardEmails extracts email properties from a jCard, which looks like
+// ["vcard", [["email", {}, "text", "a@example.com"], ...]].
+func vcardEmails(vcard []any) []string {
+	if len(vcard) != 2 {
+		return nil
+	}
+	props, _ := vcard[1].([]any)
+
+	var emails []string
+	for _, p := range props {
+		prop, _ := p.([]any)
+		if len(prop) < 4 || prop[0] != "email" {
+			continue
+		}
+		if email, ok := prop[3].(string); ok && emailRegex.MatchString(email) {
+			emails = append(emails, email)
+		}
+	}
+	return emails
+}
+
+// whoisContactRoles maps words in WHOIS field names to contact roles.
+var whoisContactRoles = []struct{ word, role string }{
+	{"abuse", "abuse"},
+	{"registrant", "registrant"},
+	{"admin", "administrative"},
+	{"tech", "technical"},
+	{"billing", "billing"},
+}
+
+func whoisContacts(ctx context.Context, domain string) ([]Contact, error) {
+	resp, err := Whois(ctx, domain)
+	if err != nil {
+		return nil, err
+	}
+
+	var contacts []Contact
+	scanner := bufio.NewScanner(strings.NewReader(resp))
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), ":")
+		if !ok {
+			continue
+		}
+		email := emailRegex.FindString(value)
+		if email == "" {
+			continue
+		}
+		role := "contact"
+		lowerKey := strings.ToLower(key)
+		for _, r := range whoisContactRoles {
+			if strings.Contains(lowerKey, r.word) {
+				role = r.role
+				break
+			}
+		}
+		contacts = append(contacts, Contact{Role: role, Email: email, Source: SourceWhois})
+	}
+	return contacts, nil
+}
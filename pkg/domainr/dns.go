@@ -0,0 +1,183 @@
+package domainr
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	precheckWorkers = 16
+	precheckTimeout = 5 * time.Second
+)
+
+// ErrNoSuchDomain is returned by DNS lookups when the domain does not exist.
+var ErrNoSuchDomain = errors.New("no such domain")
+
+// LookupNameservers returns the delegated nameservers for domain, lowercased
+// and without trailing dots. It returns ErrNoSuchDomain on NXDOMAIN.
+func LookupNameservers(ctx context.Context, domain string) ([]string, error) {
+	r, _ := currentResolver()
+	records, err := r.LookupNS(ctx, domain)
+	if err != nil {
+		var dnsErr *net.DNSError
+		if errors.As(err, &dnsErr) && dnsErr.IsNotFound {
+			return nil, ErrNoSuchDomain
+		}
+		return nil, err
+	}
+
+	hosts := make([]string, 0, len(records))
+	for _, ns := range records {
+		hosts = append(hosts, NormalizeHost(ns.Host))
+	}
+	sort.Strings(hosts)
+	return hosts, nil
+}
+
+// NormalizeHost lowercases a hostname and strips its trailing dot.
+func NormalizeHost(host string) string {
+	return strings.ToLower(strings.TrimSuffix(host, "."))
+}
+
+// DNSStatus guesses availability from DNS alone: a delegated domain is taken,
+// and one with no delegation is probably unregistered. Registered domains
+// without nameservers (e.g. on hold) are misreported as available, so callers
+// should treat the answer as a heuristic.
+func DNSStatus(ctx context.Context, domain string) (DomainStatus, error) {
+	_, err := LookupNameservers(ctx, domain)
+	switch {
+	case err == nil:
+		return StatusTaken, nil
+	case errors.Is(err, ErrNoSuchDomain):
+		return StatusAvailable, nil
+	default:
+		return StatusUnknown, err
+	}
+}
+
+// wildcardProbes caches HasWildcardDNS answers by resolver and TLD.
+var wildcardProbes sync.Map
+
+// HasWildcardDNS reports whether names under tld that can't exist still
+// resolve, either because the registry wildcards the zone or because the
+// resolver rewrites NXDOMAIN answers, as some ISP and corporate resolvers do.
+// DNS can't tell registered names from free ones there. It probes a random
+// label, and answers are cached for each resolver.
+func HasWildcardDNS(ctx context.Context, tld string) (bool, error) {
+	tld = NormalizeHost(strings.TrimPrefix(tld, "."))
+	_, addr := currentResolver()
+	key := addr + " " + tld
+	if v, ok := wildcardProbes.Load(key); ok {
+		return v.(bool), nil
+	}
+
+	buf := make([]byte, 8)
+	rand.Read(buf)
+	probe := "domainr-probe-" + hex.EncodeToString(buf) + "." + tld
+
+	_, err := LookupNameservers(ctx, probe)
+	if errors.Is(err, ErrNoSuchDomain) {
+		r, _ := currentResolver()
+		_, err = r.LookupHost(ctx, probe)
+		var dnsErr *net.DNSError
+		if errors.As(err, &dnsErr) && dnsErr.IsNotFound {
+			wildcardProbes.Store(key, false)
+			return false, nil
+		}
+	}
+	if err != nil {
+		return false, err
+	}
+	wildcardProbes.Store(key, true)
+	return true, nil
+}
+
+// precheckDNS looks up nameservers for the domains not already in skip,
+// returning Taken results for the delegated ones by input index. Anything
+// else, including lookup failures, is left for the backend, as are all
+// domains under TLDs where HasWildcardDNS finds the answers untrustworthy.
+func precheckDNS(ctx context.Context, domains []string, skip map[int]DomainResult) map[int]DomainResult {
+	taken := make(map[int]DomainResult)
+	for i, r := range dnsResults(ctx, domains, skip) {
+		if r.Status == StatusTaken {
+			taken[i] = r
+		}
+	}
+	return taken
+}
+
+// dnsResults looks up nameservers for the domains not already in skip,
+// returning what DNS suggests by input index, as DNSStatus does: Taken for
+// delegated domains and Available for nonexistent ones. Lookup failures and
+// domains under TLDs where HasWildcardDNS finds the answers untrustworthy
+// are left out.
+func dnsResults(ctx context.Context, domains []string, skip map[int]DomainResult) map[int]DomainResult {
+	var mu sync.Mutex
+	results := make(map[int]DomainResult)
+
+	trusted := make(map[string]bool)
+	for i, d := range domains {
+		if _, ok := skip[i]; ok {
+			continue
+		}
+		tld := domainTLD(d)
+		if _, probed := trusted[tld]; probed {
+			continue
+		}
+		probeCtx, cancel := context.WithTimeout(ctx, precheckTimeout)
+		wildcard, err := HasWildcardDNS(probeCtx, tld)
+		cancel()
+		trusted[tld] = err == nil && !wildcard
+	}
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, precheckWorkers)
+	for i, d := range domains {
+		if _, ok := skip[i]; ok || !trusted[domainTLD(d)] {
+			continue
+		}
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			wg.Wait()
+			return results
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			lookupCtx, cancel := context.WithTimeout(ctx, precheckTimeout)
+			defer cancel()
+			ns, err := LookupNameservers(lookupCtx, strings.ToLower(d))
+			var status DomainStatus
+			switch {
+			case err == nil && len(ns) > 0:
+				status = StatusTaken
+			case errors.Is(err, ErrNoSuchDomain):
+				status = StatusAvailable
+			default:
+				return
+			}
+			mu.Lock()
+			results[i] = DomainResult{Domain: d, Status: status, Source: SourceDNS, CheckedAt: time.Now()}
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	return results
+}
+
+// domainTLD returns everything after domain's first label, lowercased, so
+// that "example.co.uk" gives "co.uk".
+func domainTLD(domain string) string {
+	_, tld, _ := strings.Cut(strings.ToLower(domain), ".")
+	return tld
+}
@@ -0,0 +1,20 @@
+// Package domainr checks domain name availability by scraping Namecheap's
+// search results with a Playwright-driven browser.
+//
+// Playwright's browsers must be installed before use:
+//
+//	go run github.com/playwright-community/playwright-go/cmd/playwright install --with-deps chromium
+//
+// Check a batch and get all results at once:
+//
+//	results, err := domainr.CheckDomains(ctx, []string{"example.com", "example.io"}, domainr.Options{Headless: true})
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	for _, r := range results {
+//		fmt.Println(r.Domain, r.Status, r.Price)
+//	}
+//
+// Or use StreamDomains to handle each result as soon as it is known. Both
+// stop when ctx is cancelled, closing the browser.
+package domainr
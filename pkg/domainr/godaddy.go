@@ -0,0 +1,248 @@
+package domainr
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	godaddyURL    = "https://api.godaddy.com"
+	godaddyOTEURL = "https://api.ote-godaddy.com"
+)
+
+// godaddyBatch is the most domains one bulk availability call accepts.
+const godaddyBatch = 500
+
+// GoDaddyConfig holds credentials for GoDaddy's domains API. Keys are
+// created on GoDaddy's developer portal, separately for its OTE test
+// environment and for production.
+type GoDaddyConfig struct {
+	APIKey    string
+	APISecret string
+	// OTE uses the test environment, which needs OTE keys.
+	OTE bool
+}
+
+// GoDaddyConfigFromEnv reads credentials from GODADDY_API_KEY and
+// GODADDY_API_SECRET, using the OTE environment when GODADDY_OTE is set to a
+// true value such as 1.
+func GoDaddyConfigFromEnv() GoDaddyConfig {
+	ote, _ := strconv.ParseBool(os.Getenv("GODADDY_OTE"))
+	return GoDaddyConfig{
+		APIKey:    os.Getenv("GODADDY_API_KEY"),
+		APISecret: os.Getenv("GODADDY_API_SECRET"),
+		OTE:       ote,
+	}
+}
+
+func (c GoDaddyConfig) validate() error {
+	var missing []string
+	if c.APIKey == "" {
+		missing = append(missing, "GODADDY_API_KEY")
+	}
+	if c.APISecret == "" {
+		missing = append(missing, "GODADDY_API_SECRET")
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("godaddy needs %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// request calls the API, decoding a successful JSON response into out.
+func (c GoDaddyConfig) request(ctx context.Context, method, path string, body, out any) error {
+	base := godaddyURL
+	if c.OTE {
+		base = godaddyOTEURL
+	}
+	var payload bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&payload).Encode(body); err != nil {
+			return err
+		}
+	}
+	req, err := http.NewRequestWithContext(ctx, method, base+path, &payload)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "sso-key "+c.APIKey+":"+c.APISecret)
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("godaddy: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		var apiErr struct {
+			Code    string `json:"code"`
+			Message string `json:"message"`
+		}
+		if json.NewDecoder(resp.Body).Decode(&apiErr) == nil && apiErr.Message != "" {
+			return fmt.Errorf("godaddy: %s (%s)", apiErr.Message, apiErr.Code)
+		}
+		return fmt.Errorf("godaddy: unexpected status %d", resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("godaddy: %w", err)
+	}
+	return nil
+}
+
+// godaddyAvailability is one domain in a bulk availability response. Price
+// is in millionths of the currency unit.
+type godaddyAvailability struct {
+	Domain    string `json:"domain"`
+	Available bool   `json:"available"`
+	Price     int64  `json:"price"`
+	Currency  string `json:"currency"`
+	Period    int    `json:"period"`
+}
+
+// streamGoDaddy checks domains with GoDaddy's bulk availability API,
+// emitting results in input order.
+func streamGoDaddy(ctx context.Context, domains []string, cfg GoDaddyConfig, emit func(DomainResult) error) error {
+	if err := cfg.validate(); err != nil {
+		return err
+	}
+
+	for start := 0; start < len(domains); start += godaddyBatch {
+		batch := domains[start:min(start+godaddyBatch, len(domains))]
+		results, err := godaddyCheck(ctx, batch, cfg)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		for _, d := range batch {
+			r, ok := results[strings.ToLower(d)]
+			switch {
+			case err != nil:
+				r = DomainResult{Status: StatusUnknown, Reason: err.Error()}
+			case !ok:
+				r = DomainResult{Status: StatusUnknown, Reason: "missing from API response"}
+			}
+			r.Domain = d
+			r.Source = SourceGoDaddy
+			r.CheckedAt = time.Now()
+			if err := emit(r); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// godaddyCheck runs one bulk availability call, returning results keyed by
+// lowercased domain. Domains GoDaddy rejected get unknown results with its
+// reason.
+func godaddyCheck(ctx context.Context, domains []string, cfg GoDaddyConfig) (map[string]DomainResult, error) {
+	var body struct {
+		Domains []godaddyAvailability `json:"domains"`
+		Errors  []struct {
+			Domain  string `json:"domain"`
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := cfg.request(ctx, http.MethodPost, "/v1/domains/available?checkType=FULL", domains, &body); err != nil {
+		return nil, err
+	}
+
+	results := make(map[string]DomainResult, len(domains))
+	for _, a := range body.Domains {
+		r := DomainResult{Status: StatusTaken}
+		if a.Available {
+			r.Status = StatusAvailable
+			r.Price = godaddyPrice(a)
+		}
+		results[strings.ToLower(a.Domain)] = r
+	}
+	for _, e := range body.Errors {
+		results[strings.ToLower(e.Domain)] = DomainResult{Status: StatusUnknown, Reason: "godaddy: " + e.Message}
+	}
+	return results, nil
+}
+
+// godaddyPrice formats a quoted price like the other backends' prices.
+func godaddyPrice(a godaddyAvailability) string {
+	if a.Price == 0 {
+		return ""
+	}
+	amount := fmt.Sprintf("%.2f", float64(a.Price)/1e6)
+	price := amount + " " + a.Currency
+	if a.Currency == "" || a.Currency == "USD" {
+		price = "$" + amount
+	}
+	if a.Period > 1 {
+		return fmt.Sprintf("%s/%dyr", price, a.Period)
+	}
+	return price + "/yr"
+}
+
+// GoDaddyPrices quotes GoDaddy's registration price for each of domains it
+// reports available, keyed by lowercased ASCII domain. Unlike Porkbun,
+// GoDaddy only quotes prices per domain and to API key holders.
+func GoDaddyPrices(ctx context.Context, domains []string, cfg GoDaddyConfig) (map[string]string, error) {
+	if cfg == (GoDaddyConfig{}) {
+		cfg = GoDaddyConfigFromEnv()
+	}
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+	ascii := make([]string, len(domains))
+	for i, d := range domains {
+		ascii[i] = ToASCII(d)
+	}
+	prices := make(map[string]string)
+	for start := 0; start < len(ascii); start += godaddyBatch {
+		results, err := godaddyCheck(ctx, ascii[start:min(start+godaddyBatch, len(ascii))], cfg)
+		if err != nil {
+			return nil, err
+		}
+		for domain, r := range results {
+			if r.Price != "" {
+				prices[domain] = r.Price
+			}
+		}
+	}
+	return prices, nil
+}
+
+// GoDaddySuggestions asks GoDaddy's suggestion API for up to limit available
+// domains related to query, optionally restricted to tlds.
+func GoDaddySuggestions(ctx context.Context, query string, tlds []string, limit int, cfg GoDaddyConfig) ([]string, error) {
+	if cfg == (GoDaddyConfig{}) {
+		cfg = GoDaddyConfigFromEnv()
+	}
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+	q := url.Values{"query": {query}, "waitMs": {"2000"}}
+	if limit > 0 {
+		q.Set("limit", strconv.Itoa(limit))
+	}
+	for _, tld := range tlds {
+		q.Add("tlds", strings.TrimPrefix(tld, "."))
+	}
+
+	var body []struct {
+		Domain string `json:"domain"`
+	}
+	if err := cfg.request(ctx, http.MethodGet, "/v1/domains/suggest?"+q.Encode(), nil, &body); err != nil {
+		return nil, err
+	}
+	domains := make([]string, 0, len(body))
+	for _, s := range body {
+		domains = append(domains, s.Domain)
+	}
+	return domains, nil
+}
@@ -0,0 +1,281 @@
+package domainr
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+// ErrUnsigned is returned by CheckDNSSEC for domains with no DS records in
+// the parent zone.
+var ErrUnsigned = errors.New("not signed (no DS records)")
+
+// LameNameserver is a delegated nameserver that doesn't serve its zone.
+type LameNameserver struct {
+	Host   string
+	Reason string
+}
+
+var (
+	dnsClient    = &dns.Client{Timeout: 5 * time.Second}
+	dnsTCPClient = &dns.Client{Net: "tcp", Timeout: 5 * time.Second}
+)
+
+// FindLameDelegations queries each of domain's delegated nameservers
+// directly and returns the ones that don't answer authoritatively for it.
+func FindLameDelegations(ctx context.Context, domain string) ([]LameNameserver, error) {
+	nameservers, err := LookupNameservers(ctx, domain)
+	if err != nil {
+		return nil, err
+	}
+
+	var lame []LameNameserver
+	for _, ns := range nameservers {
+		reason, err := authoritativeFor(ctx, ns, domain)
+		if err != nil {
+			return nil, err
+		}
+		if reason != "" {
+			lame = append(lame, LameNameserver{Host: ns, Reason: reason})
+		}
+	}
+	return lame, nil
+}
+
+// authoritativeFor asks ns for domain's SOA and describes why the answer is
+// lame, or returns "" if ns is authoritative. Errors are only returned when
+// ctx is done.
+func authoritativeFor(ctx context.Context, ns, domain string) (string, error) {
+	r, _ := currentResolver()
+	addrs, err := r.LookupHost(ctx, ns)
+	if ctx.Err() != nil {
+		return "", ctx.Err()
+	}
+	if err != nil {
+		return "nameserver does not resolve", nil
+	}
+
+	reason := "no response"
+	for _, addr := range addrs {
+		r, err := exchange(ctx, net.JoinHostPort(addr, "53"), domain, dns.TypeSOA, false)
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
+		switch {
+		case err != nil:
+			continue
+		case r.Rcode != dns.RcodeSuccess:
+			reason = "answered " + dns.RcodeToString[r.Rcode]
+		case !r.Authoritative:
+			reason = "not authoritative"
+		default:
+			return "", nil
+		}
+	}
+	return reason, nil
+}
+
+// CheckDNSSEC verifies the link between domain's DS records in its parent zone
+// and its own keys: a DNSKEY must match a DS record and sign the DNSKEY set,
+// and the zone's SOA must carry a valid signature. DS records come from the
+// configured resolver, so the chain above the parent is trusted rather than
+// validated. It returns ErrUnsigned if the domain has no DS records.
+func CheckDNSSEC(ctx context.Context, domain string) error {
+	resp, err := recursiveExchange(ctx, query(domain, dns.TypeDS, true))
+	if err != nil {
+		return fmt.Errorf("querying DS records: %w", err)
+	}
+	var ds []*dns.DS
+	for _, rr := range resp.Answer {
+		if d, ok := rr.(*dns.DS); ok {
+			ds = append(ds, d)
+		}
+	}
+	if len(ds) == 0 {
+		return ErrUnsigned
+	}
+
+	server, err := authoritativeServer(ctx, domain)
+	if err != nil {
+		return err
+	}
+
+	keySet, keySigs, err := signedRRset(ctx, server, domain, dns.TypeDNSKEY)
+	if err != nil {
+		return err
+	}
+	var keys, anchors []*dns.DNSKEY
+	for _, rr := range keySet {
+		k := rr.(*dns.DNSKEY)
+		keys = append(keys, k)
+		for _, d := range ds {
+			if k.KeyTag() != d.KeyTag {
+				continue
+			}
+			if digest := k.ToDS(d.DigestType); digest != nil && strings.EqualFold(digest.Digest, d.Digest) {
+				anchors = append(anchors, k)
+			}
+		}
+	}
+	if len(keys) == 0 {
+		return errors.New("DS records are published but the zone has no DNSKEY")
+	}
+	if len(anchors) == 0 {
+		return errors.New("no DNSKEY matches the parent's DS records")
+	}
+	if err := verifyRRset(keySet, keySigs, anchors); err != nil {
+		return fmt.Errorf("DNSKEY set: %w", err)
+	}
+
+	soa, soaSigs, err := signedRRset(ctx, server, domain, dns.TypeSOA)
+	if err != nil {
+		return err
+	}
+	if err := verifyRRset(soa, soaSigs, keys); err != nil {
+		return fmt.Errorf("SOA: %w", err)
+	}
+	return nil
+}
+
+// signedRRset fetches the records of qtype for domain from server along
+// with the signatures covering them.
+func signedRRset(ctx context.Context, server, domain string, qtype uint16) ([]dns.RR, []*dns.RRSIG, error) {
+	resp, err := exchange(ctx, server, domain, qtype, false)
+	if err != nil {
+		return nil, nil, fmt.Errorf("querying %s: %w", dns.TypeToString[qtype], err)
+	}
+
+	var rrset []dns.RR
+	var sigs []*dns.RRSIG
+	for _, rr := range resp.Answer {
+		switch {
+		case rr.Header().Rrtype == qtype:
+			rrset = append(rrset, rr)
+		case rr.Header().Rrtype == dns.TypeRRSIG && rr.(*dns.RRSIG).TypeCovered == qtype:
+			sigs = append(sigs, rr.(*dns.RRSIG))
+		}
+	}
+	return rrset, sigs, nil
+}
+
+// verifyRRset checks that at least one of sigs is current and was made over
+// rrset by one of keys.
+func verifyRRset(rrset []dns.RR, sigs []*dns.RRSIG, keys []*dns.DNSKEY) error {
+	if len(sigs) == 0 {
+		return errors.New("no signatures")
+	}
+
+	err := errors.New("no signature from a matching key")
+	for _, sig := range sigs {
+		if !sig.ValidityPeriod(time.Now()) {
+			err = errors.New("signature expired or not yet valid")
+			continue
+		}
+		for _, k := range keys {
+			if k.KeyTag() != sig.KeyTag || k.Algorithm != sig.Algorithm {
+				continue
+			}
+			if verr := sig.Verify(k, rrset); verr != nil {
+				err = fmt.Errorf("bad signature: %w", verr)
+				continue
+			}
+			return nil
+		}
+	}
+	return err
+}
+
+// authoritativeServer returns the address of the first reachable nameserver
+// delegated for domain.
+func authoritativeServer(ctx context.Context, domain string) (string, error) {
+	nameservers, err := LookupNameservers(ctx, domain)
+	if err != nil {
+		return "", fmt.Errorf("looking up nameservers: %w", err)
+	}
+	r, _ := currentResolver()
+	for _, ns := range nameservers {
+		addrs, err := r.LookupHost(ctx, ns)
+		if err == nil && len(addrs) > 0 {
+			return net.JoinHostPort(addrs[0], "53"), nil
+		}
+	}
+	if ctx.Err() != nil {
+		return "", ctx.Err()
+	}
+	return "", errors.New("no nameserver address resolves")
+}
+
+// recursiveServer returns the first resolver from /etc/resolv.conf, falling
+// back to a public one.
+func recursiveServer() string {
+	conf, err := dns.ClientConfigFromFile("/etc/resolv.conf")
+	if err != nil || len(conf.Servers) == 0 {
+		return "1.1.1.1:53"
+	}
+	return net.JoinHostPort(conf.Servers[0], conf.Port)
+}
+
+// exchange sends a DNSSEC-enabled query to server.
+func exchange(ctx context.Context, server, name string, qtype uint16, recursive bool) (*dns.Msg, error) {
+	return exchangeMsg(ctx, server, query(name, qtype, recursive))
+}
+
+// query builds a DNSSEC-enabled query for name.
+func query(name string, qtype uint16, recursive bool) *dns.Msg {
+	m := new(dns.Msg)
+	m.SetQuestion(dns.Fqdn(name), qtype)
+	m.RecursionDesired = recursive
+	m.SetEdns0(4096, true)
+	return m
+}
+
+// exchangeMsg sends m to server, retrying over TCP when the UDP answer is
+// truncated.
+func exchangeMsg(ctx context.Context, server string, m *dns.Msg) (*dns.Msg, error) {
+	r, _, err := dnsClient.ExchangeContext(ctx, m, server)
+	if err == nil && r.Truncated {
+		r, _, err = dnsTCPClient.ExchangeContext(ctx, m, server)
+	}
+	return r, err
+}
+
+// CertExpiry connects to domain over HTTPS and returns when its certificate
+// expires. Expired certificates are returned without error so callers can
+// report the date; other verification failures, such as a name mismatch or
+// an untrusted issuer, are returned as errors.
+func CertExpiry(ctx context.Context, domain string) (time.Time, error) {
+	dialer := &tls.Dialer{
+		NetDialer: &net.Dialer{Timeout: 10 * time.Second},
+		// Verified below, where an expired certificate can be told apart
+		Config: &tls.Config{ServerName: domain, InsecureSkipVerify: true},
+	}
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(domain, "443"))
+	if err != nil {
+		return time.Time{}, err
+	}
+	defer conn.Close()
+
+	certs := conn.(*tls.Conn).ConnectionState().PeerCertificates
+	if len(certs) == 0 {
+		return time.Time{}, errors.New("no certificate presented")
+	}
+	leaf := certs[0]
+
+	intermediates := x509.NewCertPool()
+	for _, c := range certs[1:] {
+		intermediates.AddCert(c)
+	}
+	_, err = leaf.Verify(x509.VerifyOptions{DNSName: domain, Intermediates: intermediates})
+	var invalid x509.CertificateInvalidError
+	if err != nil && !(errors.As(err, &invalid) && invalid.Reason == x509.Expired) {
+		return leaf.NotAfter, err
+	}
+	return leaf.NotAfter, nil
+}
@@ -0,0 +1,62 @@
+package domainr
+
+import (
+	"strings"
+
+	"golang.org/x/net/idna"
+)
+
+// ToASCII returns domain's ASCII form, with internationalized labels in
+// punycode ("xn--"). Names that aren't valid IDNs are returned unchanged.
+func ToASCII(domain string) string {
+	if a, err := idna.Lookup.ToASCII(domain); err == nil {
+		return a
+	}
+	return domain
+}
+
+// ToUnicode returns domain with punycode labels decoded to their native
+// script. Names that aren't valid IDNs are returned unchanged.
+func ToUnicode(domain string) string {
+	if u, err := idna.Display.ToUnicode(domain); err == nil {
+		return u
+	}
+	return domain
+}
+
+// withIDNForms wraps emit to fill in both forms of each result's domain.
+func withIDNForms(emit func(DomainResult) error) func(DomainResult) error {
+	return func(r DomainResult) error {
+		r.ASCII = ToASCII(r.Domain)
+		r.Unicode = ToUnicode(r.Domain)
+		return emit(r)
+	}
+}
+
+// asciiDomains returns domains in ASCII form, along with the form each
+// converted one was given in, keyed by its lowercased ASCII form.
+func asciiDomains(domains []string) ([]string, map[string]string) {
+	ascii := make([]string, len(domains))
+	given := make(map[string]string)
+	for i, d := range domains {
+		ascii[i] = ToASCII(d)
+		if ascii[i] != d {
+			given[strings.ToLower(ascii[i])] = d
+		}
+	}
+	return ascii, given
+}
+
+// withGivenNames wraps emit to restore the form requested domains were given
+// in, as recorded by asciiDomains.
+func withGivenNames(given map[string]string, emit func(DomainResult) error) func(DomainResult) error {
+	if len(given) == 0 {
+		return emit
+	}
+	return func(r DomainResult) error {
+		if d, ok := given[strings.ToLower(ToASCII(r.Domain))]; ok && !r.Related {
+			r.Domain = d
+		}
+		return emit(r)
+	}
+}
@@ -0,0 +1,41 @@
+package domainr
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"time"
+)
+
+const (
+	// lockWait is how long lockFile waits for another process's lock.
+	lockWait = 5 * time.Second
+	// lockStale is the age past which a lock is taken to be left behind by
+	// a process that died holding it.
+	lockStale = 30 * time.Second
+)
+
+// lockFile takes an exclusive lock by creating path, which works the same on
+// every platform, and returns a function releasing it. It waits up to
+// lockWait for another process to release the lock.
+func lockFile(path string) (func(), error) {
+	deadline := time.Now().Add(lockWait)
+	for {
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+		if err == nil {
+			f.Close()
+			return func() { os.Remove(path) }, nil
+		}
+		if !errors.Is(err, fs.ErrExist) {
+			return nil, err
+		}
+		if info, err := os.Stat(path); err == nil && time.Since(info.ModTime()) > lockStale {
+			os.Remove(path)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, err
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
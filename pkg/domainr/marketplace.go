@@ -0,0 +1,26 @@
+package domainr
+
+import "net/url"
+
+// MarketplaceLink points to where a domain may be listed for sale.
+type MarketplaceLink struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+// MarketplaceLinks returns links to domain's pages on the major aftermarket
+// marketplaces, where premium domains held by investors are usually listed.
+func MarketplaceLinks(domain string) []MarketplaceLink {
+	d := url.PathEscape(domain)
+	return []MarketplaceLink{
+		{Name: "Sedo", URL: "https://sedo.com/search/details/?domain=" + url.QueryEscape(domain)},
+		{Name: "Afternic", URL: "https://www.afternic.com/domain/" + d},
+		{Name: "Namecheap", URL: PurchaseURL(domain)},
+	}
+}
+
+// PurchaseURL returns the Namecheap page where domain can be added to the
+// cart.
+func PurchaseURL(domain string) string {
+	return "https://www.namecheap.com/domains/registration/results/?domain=" + url.QueryEscape(domain)
+}
@@ -0,0 +1,91 @@
+package domainr
+
+import "testing"
+
+func TestCardVariant(t *testing.T) {
+	tests := []struct {
+		name    string
+		classes string
+		text    string
+		status  DomainStatus
+		reason  string
+		ok      bool
+	}{
+		{
+			name:    "ordinary available",
+			classes: "domain-item available",
+			text:    "example.com $10.28/yr Add to cart",
+		},
+		{
+			name:    "ordinary taken",
+			classes: "domain-item unavailable",
+			text:    "example.com Taken Make offer on the marketplace",
+		},
+		{
+			name:    "available with offer badge",
+			classes: "domain-item available offer",
+			text:    "example.io Special offer $29.88/yr",
+		},
+		{
+			name:    "available with restricted promo copy",
+			classes: "domain-item available",
+			text:    "example.dev Unrestricted registration, no restricted features. $12.98/yr",
+		},
+		{
+			name:    "make offer class",
+			classes: "domain-item unavailable make-offer",
+			text:    "example.com Make offer",
+			status:  StatusMakeOffer,
+			reason:  ReasonAftermarketOffer,
+			ok:      true,
+		},
+		{
+			name:    "reserved class",
+			classes: "domain-item unavailable reserved",
+			text:    "example.ai",
+			status:  StatusReserved,
+			reason:  ReasonRegistryReserved,
+			ok:      true,
+		},
+		{
+			name:    "reserved label",
+			classes: "domain-item unavailable",
+			text:    "example.ai  Reserved\n by the registry",
+			status:  StatusReserved,
+			reason:  ReasonRegistryReserved,
+			ok:      true,
+		},
+		{
+			name:    "restricted class",
+			classes: "domain-item available restricted",
+			text:    "example.bank",
+			status:  StatusRestricted,
+			reason:  ReasonEligibilityRequired,
+			ok:      true,
+		},
+		{
+			name:    "restricted label",
+			classes: "domain-item available",
+			text:    "example.us Eligibility requirements apply $8.98/yr",
+			status:  StatusRestricted,
+			reason:  ReasonEligibilityRequired,
+			ok:      true,
+		},
+		{
+			name:    "premium class",
+			classes: "domain-item available premium",
+			text:    "ex.io $2,500.00",
+			status:  StatusPremium,
+			reason:  ReasonPremiumPrice,
+			ok:      true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, reason, ok := cardVariant(tt.classes, tt.text)
+			if status != tt.status || reason != tt.reason || ok != tt.ok {
+				t.Errorf("cardVariant() = %v, %q, %v, want %v, %q, %v", status, reason, ok, tt.status, tt.reason, tt.ok)
+			}
+		})
+	}
+}
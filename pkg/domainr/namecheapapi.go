@@ -0,0 +1,187 @@
+package domainr
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const namecheapAPIURL = "https://api.namecheap.com/xml.response"
+
+// namecheapAPIBatch is the most domains domains.check accepts per call.
+const namecheapAPIBatch = 50
+
+// NamecheapAPIConfig holds credentials for Namecheap's official API. The API
+// must be enabled for the account and ClientIP whitelisted in its settings.
+type NamecheapAPIConfig struct {
+	APIUser  string
+	APIKey   string
+	UserName string
+	ClientIP string
+}
+
+// NamecheapAPIConfigFromEnv reads credentials from NAMECHEAP_API_USER,
+// NAMECHEAP_API_KEY, NAMECHEAP_USERNAME (default: the API user) and
+// NAMECHEAP_CLIENT_IP.
+func NamecheapAPIConfigFromEnv() NamecheapAPIConfig {
+	c := NamecheapAPIConfig{
+		APIUser:  os.Getenv("NAMECHEAP_API_USER"),
+		APIKey:   os.Getenv("NAMECHEAP_API_KEY"),
+		UserName: os.Getenv("NAMECHEAP_USERNAME"),
+		ClientIP: os.Getenv("NAMECHEAP_CLIENT_IP"),
+	}
+	if c.UserName == "" {
+		c.UserName = c.APIUser
+	}
+	return c
+}
+
+func (c NamecheapAPIConfig) validate() error {
+	var missing []string
+	if c.APIUser == "" {
+		missing = append(missing, "NAMECHEAP_API_USER")
+	}
+	if c.APIKey == "" {
+		missing = append(missing, "NAMECHEAP_API_KEY")
+	}
+	if c.ClientIP == "" {
+		missing = append(missing, "NAMECHEAP_CLIENT_IP")
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("namecheap-api backend needs %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// namecheapAPIResponse is the subset of a domains.check response we read.
+type namecheapAPIResponse struct {
+	Status string `xml:"Status,attr"`
+	Errors []struct {
+		Number string `xml:"Number,attr"`
+		Text   string `xml:",chardata"`
+	} `xml:"Errors>Error"`
+	Results []struct {
+		Domain       string `xml:"Domain,attr"`
+		Available    bool   `xml:"Available,attr"`
+		ErrorNo      string `xml:"ErrorNo,attr"`
+		Description  string `xml:"Description,attr"`
+		IsPremium    bool   `xml:"IsPremiumName,attr"`
+		PremiumPrice string `xml:"PremiumRegistrationPrice,attr"`
+		RenewalPrice string `xml:"PremiumRenewalPrice,attr"`
+	} `xml:"CommandResponse>DomainCheckResult"`
+}
+
+// streamNamecheapAPI checks domains with Namecheap's domains.check API in
+// batches, emitting results in input order.
+func streamNamecheapAPI(ctx context.Context, domains []string, cfg NamecheapAPIConfig, emit func(DomainResult) error) error {
+	if err := cfg.validate(); err != nil {
+		return err
+	}
+
+	for start := 0; start < len(domains); start += namecheapAPIBatch {
+		batch := domains[start:min(start+namecheapAPIBatch, len(domains))]
+		results, err := namecheapAPICheck(ctx, batch, cfg)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		for _, d := range batch {
+			r, ok := results[strings.ToLower(d)]
+			switch {
+			case err != nil:
+				r = DomainResult{Status: StatusUnknown, Reason: err.Error()}
+			case !ok:
+				r = DomainResult{Status: StatusUnknown, Reason: "missing from API response"}
+			}
+			r.Domain = d
+			r.Source = SourceNamecheapAPI
+			r.CheckedAt = time.Now()
+			if err := emit(r); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// namecheapAPICheck runs one domains.check call, returning results keyed by
+// lowercased domain.
+func namecheapAPICheck(ctx context.Context, domains []string, cfg NamecheapAPIConfig) (map[string]DomainResult, error) {
+	q := url.Values{
+		"ApiUser":    {cfg.APIUser},
+		"ApiKey":     {cfg.APIKey},
+		"UserName":   {cfg.UserName},
+		"ClientIp":   {cfg.ClientIP},
+		"Command":    {"namecheap.domains.check"},
+		"DomainList": {strings.ToLower(strings.Join(domains, ","))},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, namecheapAPIURL+"?"+q.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		// Drop the request URL, which carries the API key
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return nil, fmt.Errorf("namecheap api: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("namecheap api: unexpected status %d", resp.StatusCode)
+	}
+
+	var body namecheapAPIResponse
+	if err := xml.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("namecheap api: %w", err)
+	}
+	if body.Status != "OK" {
+		var errs []error
+		for _, e := range body.Errors {
+			errs = append(errs, fmt.Errorf("namecheap api: %s (%s)", strings.TrimSpace(e.Text), e.Number))
+		}
+		if len(errs) == 0 {
+			errs = append(errs, fmt.Errorf("namecheap api: status %q", body.Status))
+		}
+		return nil, errors.Join(errs...)
+	}
+
+	results := make(map[string]DomainResult, len(body.Results))
+	for _, r := range body.Results {
+		var result DomainResult
+		switch {
+		case r.ErrorNo != "" && r.ErrorNo != "0":
+			result.Reason = r.Description
+		case !r.Available:
+			result.Status = StatusTaken
+		case r.IsPremium:
+			result.Status = StatusPremium
+			result.Reason = ReasonPremiumPrice
+			result.Price = formatAPIPrice(r.PremiumPrice)
+			result.RenewalPrice = formatAPIPrice(r.RenewalPrice)
+		default:
+			result.Status = StatusAvailable
+		}
+		results[strings.ToLower(r.Domain)] = result
+	}
+	return results, nil
+}
+
+// formatAPIPrice renders an API amount such as "13000.0000" as "$13000.00",
+// or "" if it is missing.
+func formatAPIPrice(amount string) string {
+	price, err := strconv.ParseFloat(amount, 64)
+	if err != nil || price <= 0 {
+		return ""
+	}
+	return fmt.Sprintf("$%.2f", price)
+}
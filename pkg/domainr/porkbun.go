@@ -0,0 +1,161 @@
+package domainr
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+const porkbunCheckURL = "https://api.porkbun.com/api/json/v3/domain/checkDomain/"
+
+// PorkbunConfig holds credentials for Porkbun's API. API access must be
+// enabled for the account in its settings.
+type PorkbunConfig struct {
+	APIKey       string
+	SecretAPIKey string
+}
+
+// PorkbunConfigFromEnv reads credentials from PORKBUN_API_KEY and
+// PORKBUN_SECRET_API_KEY.
+func PorkbunConfigFromEnv() PorkbunConfig {
+	return PorkbunConfig{
+		APIKey:       os.Getenv("PORKBUN_API_KEY"),
+		SecretAPIKey: os.Getenv("PORKBUN_SECRET_API_KEY"),
+	}
+}
+
+func (c PorkbunConfig) validate() error {
+	var missing []string
+	if c.APIKey == "" {
+		missing = append(missing, "PORKBUN_API_KEY")
+	}
+	if c.SecretAPIKey == "" {
+		missing = append(missing, "PORKBUN_SECRET_API_KEY")
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("porkbun backend needs %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// porkbunCheckResponse is the subset of a checkDomain response we read.
+// Porkbun encodes numbers and booleans as strings.
+type porkbunCheckResponse struct {
+	Status   string `json:"status"`
+	Message  string `json:"message"`
+	Response struct {
+		Avail          string `json:"avail"`
+		Price          string `json:"price"`
+		FirstYearPromo string `json:"firstYearPromo"`
+		Premium        string `json:"premium"`
+		Additional     struct {
+			Renewal struct {
+				Price string `json:"price"`
+			} `json:"renewal"`
+		} `json:"additional"`
+	} `json:"response"`
+	// Limits describes the rate limit: at most Limit checks every TTL
+	// seconds, of which Used have been spent.
+	Limits struct {
+		TTL   json.Number `json:"TTL"`
+		Limit json.Number `json:"limit"`
+		Used  json.Number `json:"used"`
+	} `json:"limits"`
+}
+
+// streamPorkbun checks domains one at a time with Porkbun's checkDomain API,
+// pacing requests to its rate limit and emitting results in input order.
+func streamPorkbun(ctx context.Context, domains []string, cfg PorkbunConfig, emit func(DomainResult) error) error {
+	if err := cfg.validate(); err != nil {
+		return err
+	}
+
+	var wait time.Duration
+	for _, d := range domains {
+		if err := sleepCtx(ctx, wait); err != nil {
+			return err
+		}
+		r, next, err := porkbunCheck(ctx, d, cfg)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err != nil {
+			r = DomainResult{Status: StatusUnknown, Reason: err.Error()}
+		}
+		wait = next
+		r.Domain = d
+		r.Source = SourcePorkbun
+		r.CheckedAt = time.Now()
+		if err := emit(r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// porkbunCheck checks one domain, also returning how long to wait before the
+// next check to stay within the rate limit.
+func porkbunCheck(ctx context.Context, domain string, cfg PorkbunConfig) (DomainResult, time.Duration, error) {
+	payload, err := json.Marshal(map[string]string{"apikey": cfg.APIKey, "secretapikey": cfg.SecretAPIKey})
+	if err != nil {
+		return DomainResult{}, 0, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, porkbunCheckURL+url.PathEscape(strings.ToLower(domain)), strings.NewReader(string(payload)))
+	if err != nil {
+		return DomainResult{}, 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return DomainResult{}, 0, fmt.Errorf("porkbun: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var body porkbunCheckResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		if resp.StatusCode != http.StatusOK {
+			return DomainResult{}, 0, fmt.Errorf("porkbun: unexpected status %d", resp.StatusCode)
+		}
+		return DomainResult{}, 0, fmt.Errorf("porkbun: %w", err)
+	}
+
+	var wait time.Duration
+	ttl, _ := body.Limits.TTL.Int64()
+	limit, _ := body.Limits.Limit.Int64()
+	if used, _ := body.Limits.Used.Int64(); limit > 0 && used >= limit {
+		wait = time.Duration(ttl) * time.Second
+	}
+	if body.Status != "SUCCESS" {
+		msg := body.Message
+		if msg == "" {
+			msg = fmt.Sprintf("status %q", body.Status)
+		}
+		return DomainResult{}, wait, errors.New("porkbun: " + msg)
+	}
+
+	var result DomainResult
+	r := body.Response
+	switch {
+	case r.Avail != "yes":
+		result.Status = StatusTaken
+	case r.Premium == "yes":
+		result.Status = StatusPremium
+		result.Reason = ReasonPremiumPrice
+	default:
+		result.Status = StatusAvailable
+	}
+	if result.Status != StatusTaken {
+		result.Price = formatAPIPrice(r.Price)
+		if renewal := formatAPIPrice(r.Additional.Renewal.Price); renewal != result.Price {
+			result.RenewalPrice = renewal
+		}
+	}
+	return result, wait, nil
+}
@@ -0,0 +1,115 @@
+package domainr
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const porkbunPricingURL = "https://api.porkbun.com/api/json/v3/pricing/get"
+
+// RegistrarPrice is what a registrar charges to register a domain.
+type RegistrarPrice struct {
+	Registrar string `json:"registrar"`
+	Price     string `json:"price"`
+}
+
+// PriceTable holds a registrar's registration prices by TLD, without the
+// leading dot.
+type PriceTable map[string]string
+
+// Lookup returns the price for domain's TLD, matching the longest suffix.
+func (t PriceTable) Lookup(domain string) (string, bool) {
+	name := strings.ToLower(domain)
+	for {
+		dot := strings.IndexByte(name, '.')
+		if dot < 0 {
+			return "", false
+		}
+		name = name[dot+1:]
+		if price, ok := t[name]; ok {
+			return price, true
+		}
+	}
+}
+
+// PorkbunPricing fetches Porkbun's public first-year registration prices.
+func PorkbunPricing(ctx context.Context) (PriceTable, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, porkbunPricingURL, strings.NewReader("{}"))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("porkbun pricing: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("porkbun pricing: unexpected status %d", resp.StatusCode)
+	}
+
+	var body struct {
+		Status  string `json:"status"`
+		Pricing map[string]struct {
+			Registration string `json:"registration"`
+		} `json:"pricing"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("porkbun pricing: %w", err)
+	}
+	if body.Status != "SUCCESS" {
+		return nil, fmt.Errorf("porkbun pricing: status %q", body.Status)
+	}
+
+	table := make(PriceTable, len(body.Pricing))
+	for tld, p := range body.Pricing {
+		if amount, err := strconv.ParseFloat(p.Registration, 64); err == nil {
+			table[strings.ToLower(tld)] = fmt.Sprintf("$%.2f", amount)
+		}
+	}
+	return table, nil
+}
+
+var priceRegex = regexp.MustCompile(`\d[\d,]*(\.\d+)?`)
+
+// currencySymbols maps price symbols to ISO 4217 codes, longest first so
+// "CA$" isn't read as "A$" or "$".
+var currencySymbols = []struct{ symbol, code string }{
+	{"US$", "USD"}, {"CA$", "CAD"}, {"AU$", "AUD"}, {"NZ$", "NZD"}, {"HK$", "HKD"},
+	{"C$", "CAD"}, {"A$", "AUD"}, {"$", "USD"},
+	{"€", "EUR"}, {"£", "GBP"}, {"¥", "JPY"}, {"₹", "INR"},
+}
+
+var currencyCodeRegex = regexp.MustCompile(`\b[A-Z]{3}\b`)
+
+// ParseCurrency returns the ISO 4217 code of a displayed price's currency,
+// from an explicit code ("12.00 EUR") or its symbol ("$10.98/yr" is USD), or
+// "" if it names none.
+func ParseCurrency(price string) string {
+	if code := currencyCodeRegex.FindString(price); code != "" {
+		return code
+	}
+	for _, c := range currencySymbols {
+		if strings.Contains(price, c.symbol) {
+			return c.code
+		}
+	}
+	return ""
+}
+
+// ParsePrice extracts the amount from a displayed price such as "$10.98/yr"
+// or "$1,299.00", ignoring the currency.
+func ParsePrice(price string) (float64, bool) {
+	m := priceRegex.FindString(price)
+	if m == "" {
+		return 0, false
+	}
+	amount, err := strconv.ParseFloat(strings.ReplaceAll(m, ",", ""), 64)
+	return amount, err == nil
+}
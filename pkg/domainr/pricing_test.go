@@ -0,0 +1,27 @@
+package domainr
+
+import "testing"
+
+func TestParsePrice(t *testing.T) {
+	tests := []struct {
+		in     string
+		want   float64
+		wantOK bool
+	}{
+		{in: "$10.98/yr", want: 10.98, wantOK: true},
+		{in: "$1,299.00", want: 1299, wantOK: true},
+		{in: "USD 34", want: 34, wantOK: true},
+		{in: "£2,500.00/yr", want: 2500, wantOK: true},
+		{in: "Only $0.99 first year", want: 0.99, wantOK: true},
+		{in: "", wantOK: false},
+		{in: "Make offer", wantOK: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, ok := ParsePrice(tt.in)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("ParsePrice() = %v, %v, want %v, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
@@ -0,0 +1,156 @@
+package domainr
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const rdapBootstrapURL = "https://data.iana.org/rdap/dns.json"
+
+var httpClient = &http.Client{Timeout: 15 * time.Second}
+
+// rdapServers maps TLDs to RDAP base URLs once the bootstrap registry has
+// been loaded. A failed load is retried on the next lookup, so long-running
+// watches recover from transient errors.
+var (
+	rdapServersMu sync.Mutex
+	rdapServers   map[string]string
+)
+
+// RDAPStatus determines whether domain is registered by querying the RDAP
+// server its TLD's registry publishes in IANA's bootstrap registry. Like
+// WHOIS it knows nothing about prices or premium status.
+func RDAPStatus(ctx context.Context, domain string) (DomainStatus, error) {
+	_, code, err := rdapQuery(ctx, domain)
+	if err != nil {
+		return StatusUnknown, err
+	}
+	switch code {
+	case http.StatusOK:
+		return StatusTaken, nil
+	case http.StatusNotFound:
+		return StatusAvailable, nil
+	case http.StatusTooManyRequests:
+		return StatusUnknown, fmt.Errorf("rdap: rate limited")
+	default:
+		return StatusUnknown, fmt.Errorf("rdap: unexpected status %d", code)
+	}
+}
+
+// streamRDAP checks each domain over RDAP, emitting results in input order.
+func streamRDAP(ctx context.Context, domains []string, emit func(DomainResult) error) error {
+	for _, d := range domains {
+		r := DomainResult{Domain: d, Source: SourceRDAP}
+		status, err := RDAPStatus(ctx, strings.ToLower(d))
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		r.Status = status
+		if err != nil {
+			r.Reason = err.Error()
+		}
+		r.CheckedAt = time.Now()
+		if err := emit(r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rdapQuery fetches the RDAP domain object, returning the body and HTTP
+// status code.
+func rdapQuery(ctx context.Context, domain string) ([]byte, int, error) {
+	base, err := rdapServer(ctx, domain)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, base+"domain/"+domain, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	req.Header.Set("Accept", "application/rdap+json")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, 0, fmt.Errorf("rdap: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, 0, fmt.Errorf("rdap: reading response: %w", err)
+	}
+	return body, resp.StatusCode, nil
+}
+
+// rdapServer returns the RDAP base URL (with trailing slash) for domain's TLD.
+func rdapServer(ctx context.Context, domain string) (string, error) {
+	rdapServersMu.Lock()
+	defer rdapServersMu.Unlock()
+	if rdapServers == nil {
+		servers, err := loadRDAPBootstrap(ctx)
+		if err != nil {
+			return "", err
+		}
+		rdapServers = servers
+	}
+
+	tld := domain[strings.LastIndex(domain, ".")+1:]
+	base, ok := rdapServers[strings.ToLower(tld)]
+	if !ok {
+		return "", fmt.Errorf("rdap: no server for .%s", tld)
+	}
+	return base, nil
+}
+
+func loadRDAPBootstrap(ctx context.Context) (map[string]string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rdapBootstrapURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetching rdap bootstrap: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching rdap bootstrap: status %d", resp.StatusCode)
+	}
+
+	// Services are pairs of [TLDs, base URLs]
+	var bootstrap struct {
+		Services [][][]string `json:"services"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&bootstrap); err != nil {
+		return nil, fmt.Errorf("decoding rdap bootstrap: %w", err)
+	}
+
+	servers := make(map[string]string)
+	for _, service := range bootstrap.Services {
+		if len(service) != 2 || len(service[1]) == 0 {
+			continue
+		}
+		base := service[1][0]
+		// Prefer HTTPS when a registry lists several URLs
+		for _, u := range service[1] {
+			if strings.HasPrefix(u, "https://") {
+				base = u
+				break
+			}
+		}
+		if !strings.HasSuffix(base, "/") {
+			base += "/"
+		}
+		for _, tld := range service[0] {
+			servers[strings.ToLower(tld)] = base
+		}
+	}
+	return servers, nil
+}
@@ -0,0 +1,191 @@
+package domainr
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Registration is who a registered domain is registered with and for how
+// long, from its RDAP or WHOIS record. Fields the record doesn't publish are
+// left empty.
+type Registration struct {
+	Registrar string     `json:"registrar,omitempty"`
+	Created   *time.Time `json:"created,omitempty"`
+	Expires   *time.Time `json:"expires,omitempty"`
+	Source    string     `json:"source"`
+}
+
+// LookupRegistration returns domain's registrar and registration dates from
+// RDAP, falling back to WHOIS for TLDs without RDAP or records missing them.
+func LookupRegistration(ctx context.Context, domain string) (Registration, error) {
+	domain = strings.ToLower(domain)
+
+	reg, rdapErr := rdapRegistration(ctx, domain)
+	if ctx.Err() != nil {
+		return Registration{}, ctx.Err()
+	}
+	if rdapErr == nil && reg.Registrar != "" && reg.Expires != nil {
+		return reg, nil
+	}
+
+	whois, whoisErr := whoisRegistration(ctx, domain)
+	if ctx.Err() != nil {
+		return Registration{}, ctx.Err()
+	}
+	switch {
+	case whoisErr == nil && (whois.Registrar != "" || whois.Expires != nil):
+		return whois, nil
+	case rdapErr == nil:
+		return reg, nil
+	case whoisErr == nil:
+		return whois, nil
+	default:
+		return Registration{}, errors.Join(rdapErr, whoisErr)
+	}
+}
+
+func rdapRegistration(ctx context.Context, domain string) (Registration, error) {
+	body, code, err := rdapQuery(ctx, domain)
+	if err != nil {
+		return Registration{}, err
+	}
+	if code != http.StatusOK {
+		return Registration{}, fmt.Errorf("rdap: unexpected status %d", code)
+	}
+
+	var obj struct {
+		Events []struct {
+			Action string    `json:"eventAction"`
+			Date   time.Time `json:"eventDate"`
+		} `json:"events"`
+		Entities []rdapEntity `json:"entities"`
+	}
+	if err := json.Unmarshal(body, &obj); err != nil {
+		return Registration{}, fmt.Errorf("rdap: %w", err)
+	}
+
+	reg := Registration{Source: SourceRDAP}
+	for _, e := range obj.Events {
+		date := e.Date
+		switch e.Action {
+		case "registration":
+			reg.Created = &date
+		case "expiration":
+			reg.Expires = &date
+		}
+	}
+	for _, e := range obj.Entities {
+		for _, role := range e.Roles {
+			if role == "registrar" {
+				reg.Registrar = vcardName(e.VCard)
+			}
+		}
+	}
+	return reg, nil
+}
+
+// vcardName returns the formatted name ("fn") property of a jCard.
+func vcardName(vcard []any) string {
+	if len(vcard) != 2 {
+		return ""
+	}
+	props, _ := vcard[1].([]any)
+	for _, p := range props {
+		prop, _ := p.([]any)
+		if len(prop) < 4 || prop[0] != "fn" {
+			continue
+		}
+		if name, ok := prop[3].(string); ok {
+			return strings.TrimSpace(name)
+		}
+	}
+	return ""
+}
+
+// WHOIS field names for the registrar and dates, lowercased. Formats vary by
+// registry, so several spellings are recognized.
+var (
+	whoisRegistrarFields = []string{"registrar", "sponsoring registrar", "registrar name"}
+	whoisCreatedFields   = []string{"creation date", "created", "created on", "registered on", "registration time", "domain registration date"}
+	whoisExpiresFields   = []string{"registry expiry date", "registrar registration expiration date", "expiration date", "expiry date", "expires", "expires on", "paid-till", "expiration time", "domain expiration date"}
+)
+
+// whoisDateLayouts are the date formats seen in WHOIS responses.
+var whoisDateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05Z",
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+	"02-Jan-2006",
+	"2006.01.02",
+	"02.01.2006",
+	"2006/01/02",
+}
+
+func whoisRegistration(ctx context.Context, domain string) (Registration, error) {
+	resp, err := Whois(ctx, domain)
+	if err != nil {
+		return Registration{}, err
+	}
+
+	reg := Registration{Source: SourceWhois}
+	scanner := bufio.NewScanner(strings.NewReader(resp))
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), ":")
+		if !ok {
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+		switch {
+		case reg.Registrar == "" && slices.Contains(whoisRegistrarFields, key):
+			reg.Registrar = value
+		case reg.Created == nil && slices.Contains(whoisCreatedFields, key):
+			reg.Created = parseWhoisDate(value)
+		case reg.Expires == nil && slices.Contains(whoisExpiresFields, key):
+			reg.Expires = parseWhoisDate(value)
+		}
+	}
+	return reg, nil
+}
+
+func parseWhoisDate(s string) *time.Time {
+	// Some registries append a zone name or note after the date
+	fields := strings.Fields(s)
+	candidates := []string{s}
+	if len(fields) > 1 {
+		candidates = append(candidates, fields[0]+" "+fields[1], fields[0])
+	}
+	for _, c := range candidates {
+		for _, layout := range whoisDateLayouts {
+			if t, err := time.Parse(layout, c); err == nil {
+				return &t
+			}
+		}
+	}
+	return nil
+}
+
+// withRegistration wraps emit to attach registration details to taken
+// results. Lookups that fail leave the result as it was.
+func withRegistration(ctx context.Context, emit func(DomainResult) error) func(DomainResult) error {
+	return func(r DomainResult) error {
+		if (r.Status == StatusTaken || r.Status == StatusMakeOffer) && !r.Related {
+			if reg, err := LookupRegistration(ctx, r.Domain); err == nil {
+				r.Registration = &reg
+			}
+		}
+		return emit(r)
+	}
+}
@@ -0,0 +1,184 @@
+package domainr
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Hosted browser providers for RemoteBrowser.Provider.
+const (
+	ProviderBrowserless = "browserless"
+	ProviderBrowserbase = "browserbase"
+)
+
+const browserbaseSessionsURL = "https://api.browserbase.com/v1/sessions"
+
+// RemoteBrowser connects to a hosted browser over the Chrome DevTools
+// Protocol instead of launching Chromium locally, for machines that can't run
+// it or to scrape from the provider's network.
+type RemoteBrowser struct {
+	// Provider is ProviderBrowserless, ProviderBrowserbase, or empty to
+	// connect to Endpoint as given.
+	Provider string
+	// Endpoint is the WebSocket URL to connect to. It defaults to the
+	// provider's own; for browserless, to the one for Region.
+	Endpoint string
+	// APIKey authenticates with the provider: the browserless token or the
+	// Browserbase API key.
+	APIKey string
+	// ProjectID is the Browserbase project sessions are created in.
+	ProjectID string
+	// Region is where the provider runs the browser, such as "sfo" or "lon"
+	// for browserless and "us-west-2" for Browserbase.
+	Region string
+	// KeepAlive is how long the provider keeps a session running. Providers
+	// end idle sessions within a minute or two by default, which a
+	// long-lived Browser such as the daemon's would otherwise have to keep
+	// replacing. Browserbase sessions with a KeepAlive also survive dropped
+	// connections, so Reconnect resumes them with their cookies.
+	KeepAlive time.Duration
+}
+
+// Enabled reports whether a remote browser is configured.
+func (r RemoteBrowser) Enabled() bool {
+	return r.Provider != "" || r.Endpoint != ""
+}
+
+// remoteSession is a provider session a Browser is connected to.
+type remoteSession struct {
+	cfg        RemoteBrowser
+	id         string // Browserbase session ID, when one was created
+	connectURL string
+}
+
+// newRemoteSession resolves the URL to connect to, creating a Browserbase
+// session first when needed.
+func newRemoteSession(cfg RemoteBrowser) (*remoteSession, error) {
+	switch cfg.Provider {
+	case "":
+		return &remoteSession{cfg: cfg, connectURL: cfg.Endpoint}, nil
+	case ProviderBrowserless:
+		endpoint := cfg.Endpoint
+		if endpoint == "" {
+			endpoint = "wss://production-" + cmp.Or(cfg.Region, "sfo") + ".browserless.io"
+		}
+		query := url.Values{}
+		if cfg.APIKey != "" {
+			query.Set("token", cfg.APIKey)
+		}
+		if cfg.KeepAlive > 0 {
+			query.Set("timeout", strconv.FormatInt(cfg.KeepAlive.Milliseconds(), 10))
+		}
+		connectURL, err := withQuery(endpoint, query)
+		if err != nil {
+			return nil, err
+		}
+		return &remoteSession{cfg: cfg, connectURL: connectURL}, nil
+	case ProviderBrowserbase:
+		if cfg.APIKey == "" {
+			return nil, errors.New("browserbase: an API key is required")
+		}
+		// The default endpoint starts a session with the project's defaults;
+		// a region or keep-alive needs one created through the API
+		if cfg.Region == "" && cfg.KeepAlive == 0 {
+			connectURL, err := withQuery(cmp.Or(cfg.Endpoint, "wss://connect.browserbase.com"), url.Values{"apiKey": {cfg.APIKey}})
+			if err != nil {
+				return nil, err
+			}
+			return &remoteSession{cfg: cfg, connectURL: connectURL}, nil
+		}
+		return createBrowserbaseSession(cfg)
+	default:
+		return nil, fmt.Errorf("unknown browser provider %q (want %s or %s)", cfg.Provider, ProviderBrowserless, ProviderBrowserbase)
+	}
+}
+
+func createBrowserbaseSession(cfg RemoteBrowser) (*remoteSession, error) {
+	if cfg.ProjectID == "" {
+		return nil, errors.New("browserbase: a project ID is required to set a region or keep-alive")
+	}
+	body := map[string]any{"projectId": cfg.ProjectID}
+	if cfg.Region != "" {
+		body["region"] = cfg.Region
+	}
+	if cfg.KeepAlive > 0 {
+		body["keepAlive"] = true
+		body["timeout"] = int(cfg.KeepAlive.Seconds())
+	}
+	var session struct {
+		ID         string `json:"id"`
+		ConnectURL string `json:"connectUrl"`
+	}
+	if err := browserbaseRequest(browserbaseSessionsURL, cfg.APIKey, body, &session); err != nil {
+		return nil, err
+	}
+	if session.ConnectURL == "" {
+		return nil, errors.New("browserbase: no connect URL for the new session")
+	}
+	return &remoteSession{cfg: cfg, id: session.ID, connectURL: session.ConnectURL}, nil
+}
+
+// release ends a kept-alive Browserbase session, which otherwise runs, and
+// is billed, until its timeout.
+func (s *remoteSession) release() error {
+	if s.id == "" || s.cfg.KeepAlive == 0 {
+		return nil
+	}
+	body := map[string]any{"projectId": s.cfg.ProjectID, "status": "REQUEST_RELEASE"}
+	return browserbaseRequest(browserbaseSessionsURL+"/"+url.PathEscape(s.id), s.cfg.APIKey, body, nil)
+}
+
+// resumable reports whether the session outlives its connection.
+func (s *remoteSession) resumable() bool {
+	return s.id != "" && s.cfg.KeepAlive > 0
+}
+
+func browserbaseRequest(endpoint, apiKey string, body any, out any) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, endpoint, strings.NewReader(string(payload)))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-BB-API-Key", apiKey)
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("browserbase: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("browserbase: unexpected status %d", resp.StatusCode)
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("browserbase: %w", err)
+	}
+	return nil
+}
+
+// withQuery adds query to endpoint's query string.
+func withQuery(endpoint string, query url.Values) (string, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return "", fmt.Errorf("invalid browser endpoint: %w", err)
+	}
+	q := u.Query()
+	for key, values := range query {
+		q[key] = values
+	}
+	u.RawQuery = q.Encode()
+	return u.String(), nil
+}
@@ -0,0 +1,162 @@
+package domainr
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+// The resolver used for nameserver lookups, the DNS precheck and the health
+// checks. resolverAddr is "" for the system resolver, host:port for plain
+// DNS, or an https:// URL for DNS over HTTPS.
+var (
+	resolverMu   sync.RWMutex
+	resolver     = net.DefaultResolver
+	resolverAddr string
+)
+
+// SetResolver sends this package's DNS lookups to addr instead of the system
+// resolver: "host" or "host:port" for plain DNS, or an https:// URL for DNS
+// over HTTPS (RFC 8484). Corporate resolvers that filter names or answer
+// NXDOMAIN with a wildcard would otherwise skew the DNS-based checks. An
+// empty addr restores the system resolver.
+func SetResolver(addr string) error {
+	r := net.DefaultResolver
+	switch {
+	case addr == "":
+	case strings.HasPrefix(addr, "https://"):
+		if _, err := url.Parse(addr); err != nil {
+			return fmt.Errorf("invalid resolver URL: %w", err)
+		}
+		r = &net.Resolver{
+			PreferGo: true,
+			Dial: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				return &dohConn{ctx: ctx, url: addr}, nil
+			},
+		}
+	default:
+		if _, _, err := net.SplitHostPort(addr); err != nil {
+			addr = net.JoinHostPort(addr, "53")
+		}
+		if host, _, _ := net.SplitHostPort(addr); host == "" {
+			return fmt.Errorf("invalid resolver %q: want host:port or an https:// URL", addr)
+		}
+		r = &net.Resolver{
+			PreferGo: true,
+			Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+				var d net.Dialer
+				return d.DialContext(ctx, network, addr)
+			},
+		}
+	}
+
+	resolverMu.Lock()
+	defer resolverMu.Unlock()
+	resolver, resolverAddr = r, addr
+	return nil
+}
+
+func currentResolver() (*net.Resolver, string) {
+	resolverMu.RLock()
+	defer resolverMu.RUnlock()
+	return resolver, resolverAddr
+}
+
+// recursiveExchange sends m to the configured resolver, or to the first one
+// in /etc/resolv.conf when none is set.
+func recursiveExchange(ctx context.Context, m *dns.Msg) (*dns.Msg, error) {
+	_, addr := currentResolver()
+	if strings.HasPrefix(addr, "https://") {
+		packed, err := m.Pack()
+		if err != nil {
+			return nil, err
+		}
+		resp, err := dohQuery(ctx, addr, packed)
+		if err != nil {
+			return nil, err
+		}
+		r := new(dns.Msg)
+		return r, r.Unpack(resp)
+	}
+	if addr == "" {
+		addr = recursiveServer()
+	}
+	return exchangeMsg(ctx, addr, m)
+}
+
+// dohQuery POSTs a wire-format DNS message to a DNS over HTTPS endpoint and
+// returns the wire-format answer.
+func dohQuery(ctx context.Context, url string, msg []byte) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(msg))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/dns-message")
+	req.Header.Set("Accept", "application/dns-message")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("dns over https: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("dns over https: unexpected status %d", resp.StatusCode)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, 65535))
+}
+
+// dohConn adapts DNS over HTTPS to the stream connection net.Resolver dials:
+// each query written with its two-byte length prefix is POSTed to url, and
+// the answer is read back with the same framing.
+type dohConn struct {
+	ctx     context.Context
+	url     string
+	in, out bytes.Buffer
+}
+
+func (c *dohConn) Write(b []byte) (int, error) {
+	c.in.Write(b)
+	for c.in.Len() >= 2 {
+		size := int(binary.BigEndian.Uint16(c.in.Bytes()))
+		if c.in.Len() < 2+size {
+			break
+		}
+		c.in.Next(2)
+		resp, err := dohQuery(c.ctx, c.url, c.in.Next(size))
+		if err != nil {
+			return 0, err
+		}
+		binary.Write(&c.out, binary.BigEndian, uint16(len(resp)))
+		c.out.Write(resp)
+	}
+	return len(b), nil
+}
+
+func (c *dohConn) Read(b []byte) (int, error) {
+	if c.out.Len() == 0 {
+		return 0, io.EOF
+	}
+	return c.out.Read(b)
+}
+
+func (c *dohConn) Close() error                     { return nil }
+func (c *dohConn) LocalAddr() net.Addr              { return dohAddr{} }
+func (c *dohConn) RemoteAddr() net.Addr             { return dohAddr{} }
+func (c *dohConn) SetDeadline(time.Time) error      { return nil }
+func (c *dohConn) SetReadDeadline(time.Time) error  { return nil }
+func (c *dohConn) SetWriteDeadline(time.Time) error { return nil }
+
+type dohAddr struct{}
+
+func (dohAddr) Network() string { return "https" }
+func (dohAddr) String() string  { return "doh" }
@@ -0,0 +1,115 @@
+package domainr
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Selectors whose matches the Namecheap scraper counts for TakeSelectorReport
+// when Options.SelectorHealth is set.
+const (
+	// SelectorResults is settled result cards appearing after a search
+	SelectorResults = "results"
+	// SelectorCard is a domain card parsing into a result
+	SelectorCard = "card"
+	// SelectorName is a card's name found under .domain-name .name rather
+	// than by the bare h2 fallback
+	SelectorName = "name"
+)
+
+// SelectorCount is how often a selector matched and missed.
+type SelectorCount struct {
+	Matched int `json:"matched"`
+	Missed  int `json:"missed"`
+}
+
+// SelectorReport is the Namecheap scraper's health since the last report:
+// per-selector match counts and the frontend builds it scraped. It holds
+// nothing about the domains checked.
+type SelectorReport struct {
+	Selectors map[string]SelectorCount `json:"selectors"`
+	Builds    []string                 `json:"frontend_builds"`
+}
+
+// selectorHealth is counted for every scrape in the process, like the
+// circuit breakers, so a daemon's report covers all the checks it served.
+var selectorHealth struct {
+	mu     sync.Mutex
+	counts map[string]SelectorCount
+	builds map[string]bool
+}
+
+func recordSelector(name string, matched bool) {
+	selectorHealth.mu.Lock()
+	defer selectorHealth.mu.Unlock()
+	if selectorHealth.counts == nil {
+		selectorHealth.counts = make(map[string]SelectorCount)
+	}
+	c := selectorHealth.counts[name]
+	if matched {
+		c.Matched++
+	} else {
+		c.Missed++
+	}
+	selectorHealth.counts[name] = c
+}
+
+func recordBuild(build string) {
+	if build == "" {
+		return
+	}
+	selectorHealth.mu.Lock()
+	defer selectorHealth.mu.Unlock()
+	if selectorHealth.builds == nil {
+		selectorHealth.builds = make(map[string]bool)
+	}
+	selectorHealth.builds[build] = true
+}
+
+// TakeSelectorReport returns the selector health counted in this process
+// since the last call, and starts counting afresh. The report is empty when
+// nothing was scraped with Options.SelectorHealth set.
+func TakeSelectorReport() SelectorReport {
+	selectorHealth.mu.Lock()
+	defer selectorHealth.mu.Unlock()
+	report := SelectorReport{
+		Selectors: selectorHealth.counts,
+		Builds:    slices.Sorted(maps.Keys(selectorHealth.builds)),
+	}
+	selectorHealth.counts, selectorHealth.builds = nil, nil
+	return report
+}
+
+// frontendBuildScript finds what identifies the deployed frontend: a build
+// ID the page declares, or else the paths of its own scripts, whose names
+// carry content hashes.
+const frontendBuildScript = `() => {
+	const next = window.__NEXT_DATA__ && window.__NEXT_DATA__.buildId;
+	if (next) return String(next);
+	const meta = document.querySelector('meta[name="build-id"], meta[name="build-version"], meta[name="version"]');
+	if (meta && meta.content) return meta.content;
+	return Array.from(document.scripts)
+		.map(s => { try { return new URL(s.src, location.href) } catch { return null } })
+		.filter(u => u && u.hostname.endsWith('namecheap.com'))
+		.map(u => u.pathname)
+		.sort()
+		.join(' ');
+}`
+
+// frontendBuild turns what frontendBuildScript found into a short ID,
+// hashing script paths and anything else too long to report as is.
+func frontendBuild(raw string) string {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return ""
+	}
+	if len(raw) <= 40 && !strings.ContainsAny(raw, " /") {
+		return raw
+	}
+	sum := sha256.Sum256([]byte(raw))
+	return hex.EncodeToString(sum[:6])
+}
@@ -0,0 +1,179 @@
+package domainr
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// Social platforms whose usernames CheckHandle looks up.
+const (
+	PlatformGitHub    = "github"
+	PlatformX         = "x"
+	PlatformInstagram = "instagram"
+)
+
+// SocialHandle is whether a username is free on a social platform.
+// Status is StatusAvailable, StatusTaken or StatusUnknown, with Reason
+// saying why.
+type SocialHandle struct {
+	Platform string       `json:"platform"`
+	Handle   string       `json:"handle"`
+	Status   DomainStatus `json:"status"`
+	URL      string       `json:"url"`
+	Reason   string       `json:"reason,omitempty"`
+}
+
+// socialPlatform knows a platform's username rules and how to look one up.
+type socialPlatform struct {
+	name    string
+	valid   *regexp.Regexp
+	profile func(handle string) string
+	lookup  func(ctx context.Context, handle string) (DomainStatus, error)
+}
+
+var socialPlatforms = []socialPlatform{
+	{
+		name:    PlatformGitHub,
+		valid:   regexp.MustCompile(`^[a-zA-Z0-9]([a-zA-Z0-9]|-[a-zA-Z0-9]){0,38}$`),
+		profile: func(h string) string { return "https://github.com/" + h },
+		lookup:  githubHandle,
+	},
+	{
+		name:    PlatformX,
+		valid:   regexp.MustCompile(`^[a-zA-Z0-9_]{4,15}$`),
+		profile: func(h string) string { return "https://x.com/" + h },
+		lookup:  xHandle,
+	},
+	{
+		name:    PlatformInstagram,
+		valid:   regexp.MustCompile(`^[a-zA-Z0-9._]{1,30}$`),
+		profile: func(h string) string { return "https://www.instagram.com/" + h + "/" },
+		lookup:  instagramHandle,
+	},
+}
+
+// CheckHandles looks up whether handle is free on GitHub, X and Instagram,
+// returning one result per platform in that order. Handles a platform
+// doesn't allow, such as hyphenated ones on X, are reported unknown.
+func CheckHandles(ctx context.Context, handle string) []SocialHandle {
+	results := make([]SocialHandle, len(socialPlatforms))
+	var wg sync.WaitGroup
+	for i, p := range socialPlatforms {
+		results[i] = SocialHandle{Platform: p.name, Handle: handle, URL: p.profile(handle)}
+		if !p.valid.MatchString(handle) {
+			results[i].Reason = "not a valid " + p.name + " username"
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			status, err := p.lookup(ctx, handle)
+			results[i].Status = status
+			if err != nil {
+				results[i].Status, results[i].Reason = StatusUnknown, err.Error()
+			}
+		}()
+	}
+	wg.Wait()
+	return results
+}
+
+// profileStatus maps a profile lookup's response status to availability:
+// a missing profile means the username is free.
+func profileStatus(platform string, resp *http.Response) (DomainStatus, error) {
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return StatusTaken, nil
+	case http.StatusNotFound:
+		return StatusAvailable, nil
+	default:
+		return StatusUnknown, fmt.Errorf("%s: unexpected status %d", platform, resp.StatusCode)
+	}
+}
+
+func githubHandle(ctx context.Context, handle string) (DomainStatus, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://api.github.com/users/"+handle, nil)
+	if err != nil {
+		return StatusUnknown, err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return StatusUnknown, fmt.Errorf("github: %w", err)
+	}
+	defer resp.Body.Close()
+	return profileStatus(PlatformGitHub, resp)
+}
+
+// xHandle asks the endpoint X's signup form uses, which also rejects
+// usernames of suspended accounts and reserved words.
+func xHandle(ctx context.Context, handle string) (DomainStatus, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://api.x.com/i/users/username_available.json?username="+url.QueryEscape(handle), nil)
+	if err != nil {
+		return StatusUnknown, err
+	}
+	req.Header.Set("User-Agent", userAgent)
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return StatusUnknown, fmt.Errorf("x: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return StatusUnknown, fmt.Errorf("x: unexpected status %d", resp.StatusCode)
+	}
+	var body struct {
+		Valid  bool   `json:"valid"`
+		Reason string `json:"reason"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return StatusUnknown, fmt.Errorf("x: %w", err)
+	}
+	if body.Valid {
+		return StatusAvailable, nil
+	}
+	return StatusTaken, nil
+}
+
+// instagramAppID identifies Instagram's web app, whose profile API answers
+// without a login.
+const instagramAppID = "936619743392459"
+
+func instagramHandle(ctx context.Context, handle string) (DomainStatus, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://www.instagram.com/api/v1/users/web_profile_info/?username="+url.QueryEscape(handle), nil)
+	if err != nil {
+		return StatusUnknown, err
+	}
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set("X-IG-App-ID", instagramAppID)
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return StatusUnknown, fmt.Errorf("instagram: %w", err)
+	}
+	defer resp.Body.Close()
+	return profileStatus(PlatformInstagram, resp)
+}
+
+// withSocials wraps emit to attach the availability of each domain's name as
+// a username, looking each name up once per run however many TLDs it is
+// checked in.
+func withSocials(ctx context.Context, emit func(DomainResult) error) func(DomainResult) error {
+	seen := make(map[string][]SocialHandle)
+	return func(r DomainResult) error {
+		if !r.Related {
+			name, _, _ := strings.Cut(strings.ToLower(r.Domain), ".")
+			handles, ok := seen[name]
+			if !ok {
+				handles = CheckHandles(ctx, name)
+				seen[name] = handles
+			}
+			r.Socials = handles
+		}
+		return emit(r)
+	}
+}
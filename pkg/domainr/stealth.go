@@ -0,0 +1,190 @@
+package domainr
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/playwright-community/playwright-go"
+)
+
+// StealthSignal is one thing bot detection looks at, as the scraping browser
+// presents it.
+type StealthSignal struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+	// Automated is set when the value gives automation away.
+	Automated bool `json:"automated"`
+	// Hint says why an automated value is a tell or how to avoid it.
+	Hint string `json:"hint,omitempty"`
+}
+
+// stealthPage is the test page; it reports what a detection script would see
+// back to the server.
+const stealthPage = `<!DOCTYPE html>
+<html><head><title>domainr stealth test</title></head><body><script>
+(async () => {
+	const s = {
+		webdriver: String(navigator.webdriver),
+		userAgent: navigator.userAgent,
+		platform: navigator.platform,
+		languages: (navigator.languages || []).join(","),
+		plugins: String(navigator.plugins ? navigator.plugins.length : 0),
+		chrome: String(typeof window.chrome !== "undefined"),
+		outer: window.outerWidth + "x" + window.outerHeight,
+		notification: typeof Notification !== "undefined" ? Notification.permission : "",
+		permission: "",
+		webgl: "",
+	};
+	try {
+		s.permission = (await navigator.permissions.query({name: "notifications"})).state;
+	} catch (e) {}
+	try {
+		const gl = document.createElement("canvas").getContext("webgl");
+		const info = gl.getExtension("WEBGL_debug_renderer_info");
+		s.webgl = info ? gl.getParameter(info.UNMASKED_RENDERER_WEBGL) : gl.getParameter(gl.RENDERER);
+	} catch (e) {
+		s.webgl = "unavailable";
+	}
+	await fetch("/report", {method: "POST", body: JSON.stringify(s)});
+})();
+</script></body></html>`
+
+// StealthTest loads a test page in the browser configured by opts, or
+// opts.Browser, with the same context setup scraping uses, and reports the
+// request headers and browser properties bot detection commonly checks. The
+// page is served locally, so nothing is sent to a third party.
+func StealthTest(ctx context.Context, opts Options) ([]StealthSignal, error) {
+	var (
+		mu      sync.Mutex
+		headers http.Header
+	)
+	reports := make(chan map[string]string, 1)
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		headers = r.Header.Clone()
+		mu.Unlock()
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		fmt.Fprint(w, stealthPage)
+	})
+	mux.HandleFunc("POST /report", func(w http.ResponseWriter, r *http.Request) {
+		var props map[string]string
+		if json.NewDecoder(r.Body).Decode(&props) == nil {
+			select {
+			case reports <- props:
+			default:
+			}
+		}
+	})
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+	server := &http.Server{Handler: mux}
+	go server.Serve(ln)
+	defer server.Close()
+
+	browser := opts.Browser
+	if browser == nil {
+		if browser, err = LaunchBrowser(opts); err != nil {
+			return nil, err
+		}
+		defer browser.Close()
+	}
+	pages, err := browser.openPages(1)
+	if err != nil {
+		return nil, err
+	}
+	defer browser.release(pages)
+	stop := context.AfterFunc(ctx, func() { pages[0].Close() })
+	defer stop()
+
+	if _, err := pages[0].Goto("http://"+ln.Addr().String()+"/", playwright.PageGotoOptions{Timeout: playwright.Float(float64(DefaultTimeout.Milliseconds()))}); err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, fmt.Errorf("loading test page: %w", err)
+	}
+	var props map[string]string
+	select {
+	case props = <-reports:
+	case <-time.After(DefaultTimeout):
+		return nil, fmt.Errorf("test page didn't report back within %v", DefaultTimeout)
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	return stealthSignals(headers, props), nil
+}
+
+// stealthSignals judges the request headers and page properties a test page
+// saw.
+func stealthSignals(headers http.Header, props map[string]string) []StealthSignal {
+	var signals []StealthSignal
+	add := func(name, value string, automated bool, hint string) {
+		s := StealthSignal{Name: name, Value: value, Automated: automated}
+		if automated {
+			s.Hint = hint
+		}
+		signals = append(signals, s)
+	}
+
+	ua := headers.Get("User-Agent")
+	add("User-Agent header", ua, strings.Contains(ua, "Headless"),
+		"headless browsers announce themselves; run headed or override the user agent")
+	if hints := headers.Get("Sec-Ch-Ua"); hints != "" || strings.Contains(ua, "Chrome/") {
+		add("Sec-CH-UA header", hints, strings.Contains(hints, "Headless"),
+			"client hints still name HeadlessChrome even when the user agent is overridden")
+	}
+	lang := headers.Get("Accept-Language")
+	add("Accept-Language header", lang, lang == "",
+		"real browsers always send preferred languages")
+	add("navigator.webdriver", props["webdriver"], props["webdriver"] == "true",
+		"the WebDriver flag is the most common automation check")
+	add("navigator.userAgent", props["userAgent"], props["userAgent"] != ua,
+		"the page sees a different user agent than the request sent")
+	add("navigator.platform", props["platform"], platformMismatch(props["userAgent"], props["platform"]),
+		"the platform contradicts the operating system in the user agent")
+	add("navigator.languages", props["languages"], props["languages"] == "",
+		"real browsers list at least one language")
+	add("navigator.plugins", props["plugins"], props["plugins"] == "0",
+		"desktop browsers report built-in PDF viewer plugins")
+	if strings.Contains(props["userAgent"], "Chrome/") {
+		add("window.chrome", props["chrome"], props["chrome"] != "true",
+			"Chrome defines window.chrome, headless builds may not")
+	}
+	add("Window size", props["outer"], props["outer"] == "0x0",
+		"headless windows have no outer dimensions")
+	add("Notification permission", props["notification"]+" / "+props["permission"],
+		props["notification"] == "denied" && props["permission"] == "prompt",
+		"headless Chrome denies notifications while claiming it would prompt")
+	webgl := props["webgl"]
+	add("WebGL renderer", webgl, webgl == "unavailable" || strings.Contains(webgl, "SwiftShader") || strings.Contains(webgl, "llvmpipe"),
+		"software rendering is typical of servers and headless browsers; try a headed browser on a desktop")
+	return signals
+}
+
+// platformMismatch reports whether navigator.platform names a different
+// operating system than the user agent.
+func platformMismatch(ua, platform string) bool {
+	var want string
+	switch {
+	case strings.Contains(ua, "Macintosh"):
+		want = "Mac"
+	case strings.Contains(ua, "Windows"):
+		want = "Win"
+	case strings.Contains(ua, "Linux"), strings.Contains(ua, "X11"):
+		want = "Linux"
+	default:
+		return false
+	}
+	return !strings.HasPrefix(platform, want)
+}
@@ -0,0 +1,66 @@
+package domainr
+
+import "strings"
+
+// TLDInfo describes the registry behind a TLD, to help choose between
+// otherwise similar ones.
+type TLDInfo struct {
+	TLD      string `json:"tld"`
+	Registry string `json:"registry"`
+	// Launched is the year the TLD opened for general registration.
+	Launched int `json:"launched"`
+	// Privacy reports whether registrant contact details can be kept out of
+	// public WHOIS, through a privacy service or registry redaction.
+	Privacy bool `json:"privacy"`
+	// DNSSEC reports whether the TLD zone is signed and accepts DS records.
+	DNSSEC bool `json:"dnssec"`
+}
+
+// tldInfo is an embedded snapshot of registry metadata for common TLDs.
+var tldInfo = map[string]TLDInfo{
+	"com":    {Registry: "Verisign", Launched: 1985, Privacy: true, DNSSEC: true},
+	"net":    {Registry: "Verisign", Launched: 1985, Privacy: true, DNSSEC: true},
+	"org":    {Registry: "Public Interest Registry", Launched: 1985, Privacy: true, DNSSEC: true},
+	"info":   {Registry: "Identity Digital", Launched: 2001, Privacy: true, DNSSEC: true},
+	"biz":    {Registry: "GoDaddy Registry", Launched: 2001, Privacy: true, DNSSEC: true},
+	"io":     {Registry: "Internet Computer Bureau", Launched: 1997, Privacy: true, DNSSEC: true},
+	"co":     {Registry: ".CO Internet", Launched: 2010, Privacy: true, DNSSEC: true},
+	"me":     {Registry: "doMEn", Launched: 2008, Privacy: true, DNSSEC: true},
+	"dev":    {Registry: "Google Registry", Launched: 2019, Privacy: true, DNSSEC: true},
+	"app":    {Registry: "Google Registry", Launched: 2018, Privacy: true, DNSSEC: true},
+	"xyz":    {Registry: "XYZ.com", Launched: 2014, Privacy: true, DNSSEC: true},
+	"tech":   {Registry: "Radix", Launched: 2015, Privacy: true, DNSSEC: true},
+	"us":     {Registry: "GoDaddy Registry", Launched: 2002, Privacy: false, DNSSEC: true},
+	"eu":     {Registry: "EURid", Launched: 2006, Privacy: true, DNSSEC: true},
+	"uk":     {Registry: "Nominet", Launched: 1985, Privacy: true, DNSSEC: true},
+	"ca":     {Registry: "CIRA", Launched: 1987, Privacy: true, DNSSEC: true},
+	"de":     {Registry: "DENIC", Launched: 1986, Privacy: true, DNSSEC: true},
+	"at":     {Registry: "nic.at", Launched: 1988, Privacy: true, DNSSEC: true},
+	"ch":     {Registry: "SWITCH", Launched: 1987, Privacy: true, DNSSEC: true},
+	"fr":     {Registry: "AFNIC", Launched: 1986, Privacy: true, DNSSEC: true},
+	"be":     {Registry: "DNS Belgium", Launched: 1988, Privacy: true, DNSSEC: true},
+	"nl":     {Registry: "SIDN", Launched: 1986, Privacy: true, DNSSEC: true},
+	"es":     {Registry: "Red.es", Launched: 1988, Privacy: true, DNSSEC: true},
+	"it":     {Registry: "Registro.it", Launched: 1987, Privacy: true, DNSSEC: true},
+	"pt":     {Registry: "DNS.PT", Launched: 1988, Privacy: true, DNSSEC: true},
+	"mx":     {Registry: "NIC México", Launched: 1989, Privacy: true, DNSSEC: true},
+	"com.br": {Registry: "Registro.br", Launched: 1989, Privacy: true, DNSSEC: true},
+}
+
+// LookupTLD returns metadata for the TLD of domain, matching the longest
+// known suffix so that e.g. "example.com.br" finds "com.br".
+func LookupTLD(domain string) (TLDInfo, bool) {
+	name := strings.ToLower(strings.TrimSuffix(domain, "."))
+	for {
+		dot := strings.IndexByte(name, '.')
+		if dot < 0 {
+			break
+		}
+		name = name[dot+1:]
+		if info, ok := tldInfo[name]; ok {
+			info.TLD = name
+			return info, true
+		}
+	}
+	return TLDInfo{}, false
+}
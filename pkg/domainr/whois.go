@@ -0,0 +1,155 @@
+package domainr
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	ianaWhoisServer = "whois.iana.org"
+	whoisTimeout    = 10 * time.Second
+)
+
+// whoisNoMatch are the lines registries answer with when a domain isn't
+// registered. They only count at the start of a line, so the same words in
+// a registered record's disclaimer or contact fields don't.
+var whoisNoMatch = []string{
+	"no match for ",                            // Verisign: .com, .net
+	"domain not found",                         // Identity Digital, PIR
+	"not found",                                // Afilias legacy
+	"no data found",                            // .co, GoDaddy Registry
+	"no entries found",                         // .se, .no
+	"no object found",                          // .tk and others
+	"the queried object does not exist",        // CentralNic
+	"this domain name has not been registered", // Nominet: .uk
+	"status: free",                             // DENIC: .de
+	"status: available",                        // EURid: .eu
+}
+
+// whoisRegistered are fields that only appear, with a value, in records of
+// registered domains.
+var whoisRegistered = []string{
+	"registrar:",
+	"creation date:",
+	"created:",
+	"registered on:",
+	"name server:",
+	"nserver:",
+}
+
+var (
+	whoisServersMu sync.Mutex
+	whoisServers   = make(map[string]string)
+)
+
+// WhoisStatus determines whether domain is registered by querying the TLD's
+// WHOIS server. It can tell registered from unregistered domains but knows
+// nothing about prices or premium status.
+func WhoisStatus(ctx context.Context, domain string) (DomainStatus, error) {
+	resp, err := Whois(ctx, domain)
+	if err != nil {
+		return StatusUnknown, err
+	}
+
+	return parseWhoisStatus(resp)
+}
+
+// parseWhoisStatus reads a WHOIS record. Registered-domain fields win over
+// no-match lines, since calling a registered domain available is the worst
+// mistake a checker can make.
+func parseWhoisStatus(resp string) (DomainStatus, error) {
+	var lines []string
+	for _, line := range strings.Split(strings.ToLower(resp), "\n") {
+		// Some registries prefix their notices with comment markers
+		lines = append(lines, strings.TrimSpace(strings.TrimLeft(line, "%# \t")))
+	}
+	for _, line := range lines {
+		for _, field := range whoisRegistered {
+			if value, ok := strings.CutPrefix(line, field); ok && strings.TrimSpace(value) != "" {
+				return StatusTaken, nil
+			}
+		}
+	}
+	for _, line := range lines {
+		for _, phrase := range whoisNoMatch {
+			if strings.HasPrefix(line, phrase) {
+				return StatusAvailable, nil
+			}
+		}
+	}
+	return StatusUnknown, fmt.Errorf("unrecognized whois response")
+}
+
+// Whois returns the raw WHOIS record for domain from its TLD's server.
+func Whois(ctx context.Context, domain string) (string, error) {
+	server, err := whoisServer(ctx, domain)
+	if err != nil {
+		return "", err
+	}
+	return whoisQuery(ctx, server, domain)
+}
+
+// whoisServer finds the WHOIS server for domain's TLD via IANA's referral,
+// caching it for the life of the process.
+func whoisServer(ctx context.Context, domain string) (string, error) {
+	tld := domain[strings.LastIndex(domain, ".")+1:]
+
+	whoisServersMu.Lock()
+	server, ok := whoisServers[tld]
+	whoisServersMu.Unlock()
+	if ok {
+		return server, nil
+	}
+
+	resp, err := whoisQuery(ctx, ianaWhoisServer, tld)
+	if err != nil {
+		return "", fmt.Errorf("finding whois server for .%s: %w", tld, err)
+	}
+	scanner := bufio.NewScanner(strings.NewReader(resp))
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), ":")
+		if ok && strings.EqualFold(strings.TrimSpace(key), "whois") && strings.TrimSpace(value) != "" {
+			server = strings.TrimSpace(value)
+			break
+		}
+	}
+	if server == "" {
+		return "", fmt.Errorf("no whois server for .%s", tld)
+	}
+
+	whoisServersMu.Lock()
+	whoisServers[tld] = server
+	whoisServersMu.Unlock()
+	return server, nil
+}
+
+func whoisQuery(ctx context.Context, server, query string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, whoisTimeout)
+	defer cancel()
+
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", net.JoinHostPort(server, "43"))
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+	deadline, _ := ctx.Deadline()
+	conn.SetDeadline(deadline)
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	if _, err := fmt.Fprintf(conn, "%s\r\n", query); err != nil {
+		return "", err
+	}
+	data, err := io.ReadAll(conn)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
@@ -0,0 +1,88 @@
+package domainr
+
+import "testing"
+
+func TestParseWhoisStatus(t *testing.T) {
+	tests := []struct {
+		name string
+		resp string
+		want DomainStatus
+	}{
+		{
+			name: "verisign no match",
+			resp: "No match for \"EXAMPLE-FREE.COM\".\n>>> Last update of whois database: 2025-01-01T00:00:00Z <<<\n\nNOTICE: The expiration date displayed in this record is the date the\nregistrar's sponsorship of the domain name registration in the registry is\ncurrently set to expire.\n",
+			want: StatusAvailable,
+		},
+		{
+			name: "identity digital not found",
+			resp: "Domain not found.\n>>> Last update of WHOIS database: 2025-01-01T00:00:00Z <<<\n",
+			want: StatusAvailable,
+		},
+		{
+			name: "afilias bare not found",
+			resp: "NOT FOUND\n",
+			want: StatusAvailable,
+		},
+		{
+			name: "nominet not registered",
+			resp: "\n    Domain name:\n        example-free.co.uk\n\n    This domain name has not been registered.\n",
+			want: StatusAvailable,
+		},
+		{
+			name: "commented no entries",
+			resp: "% Copyright notice\n%\n% No entries found.\n",
+			want: StatusAvailable,
+		},
+		{
+			name: "denic free",
+			resp: "Domain: example-free.de\nStatus: free\n",
+			want: StatusAvailable,
+		},
+		{
+			name: "registered",
+			resp: "   Domain Name: EXAMPLE.COM\n   Registrar: Example Registrar, Inc.\n   Creation Date: 1995-08-14T04:00:00Z\n   Name Server: A.IANA-SERVERS.NET\n",
+			want: StatusTaken,
+		},
+		{
+			name: "registered with not found in a disclaimer",
+			resp: "Domain Name: EXAMPLE.IO\nRegistrar: Example Registrar\nCreation Date: 2010-01-01T00:00:00Z\n\nIf the information you are looking for is not found here, contact the registrar.\n",
+			want: StatusTaken,
+		},
+		{
+			name: "registered with not found contact",
+			resp: "Domain Name: EXAMPLE.AI\nRegistrant Organization: not found\nName Server: ns1.example.ai\n",
+			want: StatusTaken,
+		},
+		{
+			name: "registered with no match line after fields",
+			resp: "Registrar: Example Registrar\nNo match for nameserver lookup\n",
+			want: StatusTaken,
+		},
+		{
+			name: "nominet registered",
+			resp: "    Domain name:\n        example.co.uk\n\n    Registrar:\n        Example Ltd [Tag = EXAMPLE]\n\n    Registered on: 26-Nov-1996\n",
+			want: StatusTaken,
+		},
+		{
+			name: "empty registrar field alone",
+			resp: "Registrar:\n",
+			want: StatusUnknown,
+		},
+		{
+			name: "rate limited",
+			resp: "Your connection limit exceeded. Please slow down and try again later.\n",
+			want: StatusUnknown,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseWhoisStatus(tt.resp)
+			if got != tt.want {
+				t.Errorf("parseWhoisStatus() = %v, want %v", got, tt.want)
+			}
+			if (err != nil) != (tt.want == StatusUnknown) {
+				t.Errorf("parseWhoisStatus() error = %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// childrenMemory returns the resident memory, in bytes, of every process
+// descended from this one: for the daemon, the Playwright driver and the
+// browser it runs. It reads /proc, so it is only supported on Linux.
+func childrenMemory() (uint64, error) {
+	if runtime.GOOS != "linux" {
+		return 0, errors.New("measuring browser memory is only supported on Linux")
+	}
+	entries, err := os.ReadDir("/proc")
+	if err != nil {
+		return 0, err
+	}
+
+	parents := make(map[int]int)
+	rss := make(map[int]uint64)
+	for _, e := range entries {
+		pid, err := strconv.Atoi(e.Name())
+		if err != nil {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join("/proc", e.Name(), "stat"))
+		if err != nil {
+			continue
+		}
+		// The command name may hold spaces and parentheses, so fields are
+		// counted from the last ")": state, ppid, ... rss is the 22nd
+		_, rest, ok := cutLast(string(data), ")")
+		if !ok {
+			continue
+		}
+		fields := strings.Fields(rest)
+		if len(fields) < 22 {
+			continue
+		}
+		ppid, _ := strconv.Atoi(fields[1])
+		pages, _ := strconv.ParseUint(fields[21], 10, 64)
+		parents[pid] = ppid
+		rss[pid] = pages * uint64(os.Getpagesize())
+	}
+
+	self := os.Getpid()
+	var total uint64
+	for pid, size := range rss {
+		for p := parents[pid]; p > 1; p = parents[p] {
+			if p == self {
+				total += size
+				break
+			}
+		}
+	}
+	return total, nil
+}
+
+func cutLast(s, sep string) (before, after string, found bool) {
+	if i := strings.LastIndex(s, sep); i >= 0 {
+		return s[:i], s[i+len(sep):], true
+	}
+	return s, "", false
+}
+
+// cgroupMemoryLimit returns the memory limit of this process's cgroup in
+// bytes, or 0 if there is none or it can't be read.
+func cgroupMemoryLimit() uint64 {
+	// cgroup v2, then v1
+	for _, path := range []string{"/sys/fs/cgroup/memory.max", "/sys/fs/cgroup/memory/memory.limit_in_bytes"} {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		limit, err := strconv.ParseUint(strings.TrimSpace(string(data)), 10, 64)
+		// v1 reports no limit as a huge number
+		if err != nil || limit >= 1<<62 {
+			return 0
+		}
+		return limit
+	}
+	return 0
+}
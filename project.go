@@ -0,0 +1,149 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/jpoz/domainr/pkg/domainr"
+)
+
+// defaultTLDs is the preset used when a command expands a bare name.
+var defaultTLDs = []string{"com", "net", "org", "io", "dev", "app"}
+
+var invalidLabelChars = regexp.MustCompile(`[^a-z0-9-]+`)
+
+func runFromProject(ctx context.Context, args []string) {
+	fs := flag.NewFlagSet("from-project", flag.ExitOnError)
+	tlds := fs.String("tlds", strings.Join(defaultTLDs, ","), "Comma-separated TLDs to check the project name against")
+	visible := fs.Bool("visible", false, "Show the browser window (useful for debugging)")
+	debug := fs.Bool("debug", false, "Log scraping diagnostics to stderr")
+	noColor := fs.Bool("no-color", false, "Disable colored output (also disabled by NO_COLOR or when stdout is not a terminal)")
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: domainr from-project [flags]\n\nCheck the name of the project in the current directory (package.json,\ngo.mod or Cargo.toml) across a list of TLDs.\n\nFlags:\n")
+		fs.PrintDefaults()
+	}
+	applyConfig(fs)
+	fs.Parse(args)
+	setColor(*noColor)
+
+	name, source, err := projectName(".")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	label := toLabel(name)
+	if label == "" {
+		fmt.Fprintf(os.Stderr, "Error: project name %q from %s has no usable characters\n", name, source)
+		os.Exit(1)
+	}
+	fmt.Fprintf(os.Stderr, "Checking %q from %s\n", label, source)
+
+	domains := expandTLDs([]string{label}, splitList(*tlds))
+	if len(domains) == 0 {
+		fmt.Fprintln(os.Stderr, "Error: no TLDs given")
+		os.Exit(1)
+	}
+
+	checkAndPrint(ctx, prepareDomains(domains), domainr.Options{Headless: !*visible, Debug: *debug}, outputConfig{})
+}
+
+// projectName finds the project name from the first manifest present in dir,
+// returning the name and the manifest it came from.
+func projectName(dir string) (string, string, error) {
+	readers := []struct {
+		file string
+		read func([]byte) (string, error)
+	}{
+		{"package.json", packageJSONName},
+		{"go.mod", goModName},
+		{"Cargo.toml", cargoName},
+	}
+	for _, r := range readers {
+		data, err := os.ReadFile(path.Join(dir, r.file))
+		if err != nil {
+			continue
+		}
+		name, err := r.read(data)
+		if err != nil {
+			return "", "", fmt.Errorf("reading %s: %w", r.file, err)
+		}
+		return name, r.file, nil
+	}
+	return "", "", errors.New("no package.json, go.mod or Cargo.toml in the current directory")
+}
+
+func packageJSONName(data []byte) (string, error) {
+	var pkg struct {
+		Name string `json:"name"`
+	}
+	if err := json.Unmarshal(data, &pkg); err != nil {
+		return "", err
+	}
+	if pkg.Name == "" {
+		return "", errors.New("no name field")
+	}
+	// Drop the npm scope: @acme/widget -> widget
+	return path.Base(pkg.Name), nil
+}
+
+var majorVersionSuffix = regexp.MustCompile(`^v[0-9]+$`)
+
+func goModName(data []byte) (string, error) {
+	scanner := bufio.NewScanner(strings.NewReader(string(data)))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 || fields[0] != "module" {
+			continue
+		}
+		// github.com/acme/widget/v2 -> widget
+		modPath := strings.Trim(fields[1], `"`)
+		if majorVersionSuffix.MatchString(path.Base(modPath)) {
+			modPath = path.Dir(modPath)
+		}
+		return path.Base(modPath), nil
+	}
+	return "", errors.New("no module directive")
+}
+
+func cargoName(data []byte) (string, error) {
+	inPackage := false
+	scanner := bufio.NewScanner(strings.NewReader(string(data)))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "[") {
+			inPackage = line == "[package]"
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if inPackage && ok && strings.TrimSpace(key) == "name" {
+			return strings.Trim(strings.TrimSpace(value), `"'`), nil
+		}
+	}
+	return "", errors.New("no [package] name")
+}
+
+// toLabel turns an arbitrary project name into a valid domain label.
+func toLabel(name string) string {
+	label := invalidLabelChars.ReplaceAllString(strings.ToLower(name), "-")
+	return strings.Trim(label, "-")
+}
+
+// splitList splits a comma-separated flag value, dropping empty entries.
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"math"
+	"strings"
+)
+
+// bigramCorpus is a sample of common English words used to learn which
+// letter pairs occur naturally.
+const bigramCorpus = `
+the be to of and a in that have it for not on with he as you do at this but
+his by from they we say her she or an will my one all would there their what
+so up out if about who get which go me when make can like time no just him
+know take people into year your good some could them see other than then now
+look only come its over think also back after use two how our work first well
+way even new want because any these give day most us great little world hand
+part child eye woman place week case point government company number group
+problem fact open seem turn start show hear play run move live believe hold
+bring happen write provide sit stand lose pay meet include continue set learn
+change lead understand watch follow stop create speak read allow add spend
+grow offer remember love consider appear buy wait serve die send expect build
+stay fall cut reach kill remain suggest raise pass sell require report decide
+pull water river stone garden light star planet ocean forest market silver
+golden simple bright rapid clever nimble mellow quiet tiger falcon maple
+harbor summit canvas pixel rocket orbit nova lumen atlas echo delta vista
+bloom spark craft forge nest hive loop dash flow wave peak ridge brook field
+meadow cedar willow amber coral ember frost glade haven lark linen mosaic
+nectar olive pearl quill raven sable timber velvet wander zephyr house home
+mother father family friend school student teacher money music story night
+morning evening winter summer spring autumn country city street road paper
+table window door letter question answer power energy nature animal between
+under again never always often together around before during without because
+better small large young early later right left black white green yellow
+orange purple window kitchen garden basket butter bottle candle castle circle
+doctor dragon engine finger flower gentle island jungle ladder lemon magic
+motor number pepper pillow pocket puzzle rabbit ribbon salmon shadow spider
+sugar tunnel turtle valley wagon wonder yellow zebra cloud shine smile sound
+travel trust truth unit value voice wheel wind wing wolf wood word yard zone
+mint fox bear bird frog lion monkey panda koala otter badger beaver camel
+`
+
+// bigramModel holds smoothed log2 probabilities of each letter following
+// another, with '^' and '$' marking the start and end of a word.
+var bigramModel = buildBigramModel(bigramCorpus)
+
+const bigramAlphabet = "abcdefghijklmnopqrstuvwxyz$"
+
+// bigramWeight is how much a pair's own frequency counts against the overall
+// frequency of its second letter when the two are interpolated.
+const bigramWeight = 0.85
+
+func buildBigramModel(corpus string) map[[2]byte]float64 {
+	counts := make(map[[2]byte]float64)
+	totals := make(map[byte]float64)
+	unigrams := make(map[byte]float64)
+	var n float64
+	for _, word := range strings.Fields(corpus) {
+		w := "^" + word + "$"
+		for i := 0; i+1 < len(w); i++ {
+			counts[[2]byte{w[i], w[i+1]}]++
+			totals[w[i]]++
+			unigrams[w[i+1]]++
+			n++
+		}
+	}
+
+	// Interpolate with add-one smoothed letter frequencies so unseen pairs
+	// are unlikely rather than impossible
+	model := make(map[[2]byte]float64)
+	for _, prev := range "^" + bigramAlphabet[:26] {
+		for _, next := range bigramAlphabet {
+			pair := [2]byte{byte(prev), byte(next)}
+			unigram := (unigrams[byte(next)] + 1) / (n + float64(len(bigramAlphabet)))
+			p := (1 - bigramWeight) * unigram
+			if totals[byte(prev)] > 0 {
+				p += bigramWeight * counts[pair] / totals[byte(prev)]
+			}
+			model[pair] = math.Log2(p)
+		}
+	}
+	return model
+}
+
+// pronounceability scores how English-like a name's letter sequence is, from
+// 0 (as surprising as uniformly random letters) towards 1. It is one minus the
+// name's per-transition entropy under the bigram model, relative to that of
+// uniformly random letters. Non-letters are ignored.
+func pronounceability(name string) float64 {
+	w := "^"
+	for _, r := range strings.ToLower(name) {
+		if r >= 'a' && r <= 'z' {
+			w += string(r)
+		}
+	}
+	w += "$"
+
+	var sum float64
+	for i := 0; i+1 < len(w); i++ {
+		sum += bigramModel[[2]byte{w[i], w[i+1]}]
+	}
+	entropy := -sum / float64(len(w)-1)
+	score := 1 - entropy/math.Log2(float64(len(bigramAlphabet)))
+	return max(score, 0)
+}
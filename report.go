@@ -0,0 +1,218 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"html/template"
+	"net"
+	"net/smtp"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/jpoz/domainr/pkg/domainr"
+)
+
+var reportTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"statusColor": reportStatusColor,
+}).Parse(`<!DOCTYPE html>
+<html>
+<body style="font-family: sans-serif">
+<h2>Domain availability report</h2>
+<p>Checked {{len .Results}} domain(s) on {{.CheckedAt.Format "Mon, 02 Jan 2006 15:04 MST"}}.</p>
+<table cellpadding="6" style="border-collapse: collapse">
+<tr style="text-align: left"><th>Domain</th><th>Status</th><th>Price</th><th>Notes</th></tr>
+{{range .Results}}<tr>
+<td><b>{{.Domain}}</b></td>
+<td style="color: {{statusColor .Status}}">{{.Status}}</td>
+<td>{{.Price}}</td>
+<td>{{.Reason}}</td>
+</tr>
+{{end}}</table>
+</body>
+</html>
+`))
+
+func reportStatusColor(s domainr.DomainStatus) string {
+	switch s {
+	case domainr.StatusAvailable:
+		return "green"
+	case domainr.StatusPremium, domainr.StatusMakeOffer:
+		return "purple"
+	case domainr.StatusTaken, domainr.StatusReserved:
+		return "red"
+	default:
+		return "orange"
+	}
+}
+
+// smtpConfig holds mail settings read from DOMAINR_SMTP_* variables.
+type smtpConfig struct {
+	Host, Port, User, Password, From string
+}
+
+func smtpConfigFromEnv() (smtpConfig, error) {
+	c := smtpConfig{
+		Host:     os.Getenv("DOMAINR_SMTP_HOST"),
+		Port:     os.Getenv("DOMAINR_SMTP_PORT"),
+		User:     os.Getenv("DOMAINR_SMTP_USER"),
+		Password: os.Getenv("DOMAINR_SMTP_PASSWORD"),
+		From:     os.Getenv("DOMAINR_SMTP_FROM"),
+	}
+	if c.Host == "" {
+		return c, errors.New("DOMAINR_SMTP_HOST is not set")
+	}
+	if c.Port == "" {
+		c.Port = "587"
+	}
+	if c.From == "" {
+		c.From = c.User
+	}
+	if c.From == "" {
+		return c, errors.New("DOMAINR_SMTP_FROM is not set")
+	}
+	return c, nil
+}
+
+func runReport(ctx context.Context, args []string) {
+	fs := flag.NewFlagSet("report", flag.ExitOnError)
+	file := fs.String("file", "", "File listing the domains to check")
+	email := fs.String("email", "", "Comma-separated addresses to send the report to")
+	schedule := fs.String("schedule", "once", "How often to send: once, hourly, daily, weekly, or a duration like 12h")
+	backend := fs.String("backend", domainr.SourceNamecheap, "How to check domains: namecheap (browser scraping), namecheap-api, porkbun, godaddy, or rdap; a comma-separated list falls back to each next backend for domains the previous one couldn't check")
+	visible := fs.Bool("visible", false, "Show the browser window (useful for debugging)")
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, `Usage: domainr report -file list.txt -email me@example.com [flags]
+
+Check a list of domains and email the results as an HTML report. With a
+schedule, keeps running and sends a new report every period.
+
+Mail is sent with the DOMAINR_SMTP_HOST, DOMAINR_SMTP_PORT (default 587),
+DOMAINR_SMTP_USER, DOMAINR_SMTP_PASSWORD and DOMAINR_SMTP_FROM variables.
+
+Flags:
+`)
+		fs.PrintDefaults()
+	}
+	applyConfig(fs)
+	fs.Parse(args)
+
+	if *file == "" || *email == "" {
+		fs.Usage()
+		os.Exit(1)
+	}
+	interval, err := parseSchedule(*schedule)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	mail, err := smtpConfigFromEnv()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	opts := domainr.Options{Headless: !*visible}
+	opts.Backend, opts.Fallbacks = splitBackends(*backend)
+
+	for {
+		if err := sendReport(ctx, *file, splitList(*email), opts, mail); err != nil {
+			if ctx.Err() != nil {
+				os.Exit(130)
+			}
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			if interval == 0 {
+				os.Exit(1)
+			}
+		}
+		if interval == 0 {
+			return
+		}
+
+		fmt.Fprintf(os.Stderr, "Next report at %s\n", time.Now().Add(interval).Format(time.DateTime))
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+	}
+}
+
+// parseSchedule converts a schedule name or duration into an interval; zero
+// means run once.
+func parseSchedule(s string) (time.Duration, error) {
+	switch s {
+	case "once":
+		return 0, nil
+	case "hourly":
+		return time.Hour, nil
+	case "daily":
+		return 24 * time.Hour, nil
+	case "weekly":
+		return 7 * 24 * time.Hour, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid schedule %q: use once, hourly, daily, weekly, or a duration", s)
+	}
+	return d, nil
+}
+
+// sendReport checks the domains listed in file, re-reading it so edits take
+// effect on the next run, and mails the report.
+func sendReport(ctx context.Context, file string, to []string, opts domainr.Options, mail smtpConfig) error {
+	domains, err := collectDomains(nil, file)
+	if err != nil {
+		return err
+	}
+	domains, _ = dedupeDomains(domains)
+	if len(domains) == 0 {
+		return fmt.Errorf("%s: no domains listed", file)
+	}
+
+	results, err := checkDomains(ctx, domains, opts)
+	if err != nil {
+		return err
+	}
+
+	var body bytes.Buffer
+	err = reportTemplate.Execute(&body, struct {
+		Results   []domainr.DomainResult
+		CheckedAt time.Time
+	}{results, time.Now()})
+	if err != nil {
+		return fmt.Errorf("rendering report: %w", err)
+	}
+
+	available := 0
+	for _, r := range results {
+		if r.Status == domainr.StatusAvailable {
+			available++
+		}
+	}
+	subject := fmt.Sprintf("domainr: %d of %d domains available", available, len(results))
+	if err := sendMail(mail, to, subject, body.String()); err != nil {
+		return fmt.Errorf("sending report: %w", err)
+	}
+	fmt.Fprintf(os.Stderr, "Sent report for %d domain(s) to %s\n", len(results), strings.Join(to, ", "))
+	return nil
+}
+
+func sendMail(c smtpConfig, to []string, subject, html string) error {
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", c.From)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(to, ", "))
+	fmt.Fprintf(&msg, "Subject: %s\r\n", subject)
+	fmt.Fprintf(&msg, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/html; charset=UTF-8\r\n\r\n")
+	msg.WriteString(html)
+
+	var auth smtp.Auth
+	if c.User != "" {
+		auth = smtp.PlainAuth("", c.User, c.Password, c.Host)
+	}
+	return smtp.SendMail(net.JoinHostPort(c.Host, c.Port), auth, c.From, to, msg.Bytes())
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseSchedule(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    time.Duration
+		wantErr bool
+	}{
+		{in: "once", want: 0},
+		{in: "hourly", want: time.Hour},
+		{in: "daily", want: 24 * time.Hour},
+		{in: "weekly", want: 7 * 24 * time.Hour},
+		{in: "90m", want: 90 * time.Minute},
+		{in: "Daily", wantErr: true},
+		{in: "0s", wantErr: true},
+		{in: "-1h", wantErr: true},
+		{in: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := parseSchedule(tt.in)
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Errorf("parseSchedule() = %v, %v, want %v (error %v)", got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
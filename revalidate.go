@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/jpoz/domainr/pkg/domainr"
+)
+
+// revalidator refreshes stale cached results in the background for the
+// long-running modes, which serve them to clients at once under
+// domainr.Options.MaxStale. Each domain is refreshed by one run at a time.
+type revalidator struct {
+	ctx context.Context
+	// check runs a refresh, recording results in the cache
+	check func(ctx context.Context, domains []string, opts domainr.Options) error
+
+	mu      sync.Mutex
+	running map[string]bool
+	wg      sync.WaitGroup
+}
+
+func newRevalidator(ctx context.Context, check func(context.Context, []string, domainr.Options) error) *revalidator {
+	return &revalidator{ctx: ctx, check: check, running: make(map[string]bool)}
+}
+
+// serveStale sets opts up to serve stale results and refresh them, when the
+// client asked for them with MaxStale and caching is on.
+func (v *revalidator) serveStale(opts domainr.Options) domainr.Options {
+	if opts.MaxStale > 0 && opts.CacheTTL > 0 {
+		opts.OnStale = func(domains []string) { v.refresh(domains, opts) }
+	}
+	return opts
+}
+
+// refresh checks domains again in the background, skipping any another
+// refresh is already checking.
+func (v *revalidator) refresh(domains []string, opts domainr.Options) {
+	v.mu.Lock()
+	var todo []string
+	for _, d := range domains {
+		key := strings.ToLower(d)
+		if !v.running[key] {
+			v.running[key] = true
+			todo = append(todo, d)
+		}
+	}
+	v.mu.Unlock()
+	if len(todo) == 0 || v.ctx.Err() != nil {
+		v.done(todo)
+		return
+	}
+
+	opts.Refresh = true
+	opts.OnStale = nil
+	v.wg.Add(1)
+	go func() {
+		defer v.wg.Done()
+		defer v.done(todo)
+		if err := v.check(v.ctx, todo, opts); err != nil && v.ctx.Err() == nil {
+			fmt.Fprintf(os.Stderr, "Error: refreshing %d stale results: %v\n", len(todo), err)
+		}
+	}()
+}
+
+func (v *revalidator) done(domains []string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	for _, d := range domains {
+		delete(v.running, strings.ToLower(d))
+	}
+}
+
+// wait blocks until running refreshes finish.
+func (v *revalidator) wait() {
+	v.wg.Wait()
+}
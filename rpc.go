@@ -0,0 +1,345 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jpoz/domainr/pkg/domainr"
+)
+
+// JSON-RPC 2.0 error codes.
+const (
+	rpcParseError     = -32700
+	rpcInvalidRequest = -32600
+	rpcMethodNotFound = -32601
+	rpcInvalidParams  = -32602
+	rpcInternalError  = -32603
+)
+
+type rpcRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *rpcError) Error() string { return e.Message }
+
+// rpcResponse answers a request. Exactly one of Result and Error is set;
+// ID is null when the request couldn't be parsed.
+type rpcResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+// rpcNotification is a message from the server that expects no reply.
+type rpcNotification struct {
+	JSONRPC string `json:"jsonrpc"`
+	Method  string `json:"method"`
+	Params  any    `json:"params"`
+}
+
+// checkParams are the params of the check and watch methods.
+type checkParams struct {
+	Domains []string `json:"domains"`
+	// TLDs expands bare keywords; defaults to defaultTLDs.
+	TLDs    []string `json:"tlds"`
+	Backend string   `json:"backend"`
+	// Interval is the watch interval as a Go duration, e.g. "6h".
+	Interval string `json:"interval"`
+}
+
+// rpcServer answers JSON-RPC requests read line by line, writing one
+// response or notification per line.
+type rpcServer struct {
+	ctx context.Context
+	w   io.Writer
+	wmu sync.Mutex
+
+	mu      sync.Mutex
+	watches map[int]context.CancelFunc
+	nextID  int
+
+	// cacheTTL and maxStale configure the cache for check and suggest;
+	// watches always check again
+	cacheTTL time.Duration
+	maxStale time.Duration
+	reval    *revalidator
+}
+
+func runRPC(ctx context.Context, args []string) {
+	fs := flag.NewFlagSet("rpc", flag.ExitOnError)
+	cacheTTL := fs.Duration("cache-ttl", 0, "Answer check and suggest with results checked within this long instead of checking again (0: always check)")
+	maxStale := fs.Duration("max-stale", 0, "Also answer with results cached up to this long past -cache-ttl, marked stale, checking them again in the background")
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, `Usage: domainr rpc
+
+Serve JSON-RPC 2.0 over stdin and stdout, one message per line, for editors
+and other tools to integrate with.
+
+Methods:
+  check    {"domains": [...], "tlds": [...], "backend": "rdap"}
+           Returns the results in input order.
+  suggest  {"keywords": [...], "tlds": [...], "max": 60, "backend": "rdap"}
+           Generates candidates like the suggest command and returns their
+           results.
+  watch    {"domains": [...], "interval": "6h", ...}
+           Returns {"watch": id}, then sends "watch.results" with the first
+           round's results, "watch.change" for each domain that becomes
+           available, and "watch.waiting" after every round.
+  unwatch  {"watch": id}
+           Stops a watch.
+
+With -cache-ttl, check and suggest reuse recent results from domainr's
+cache, and with -max-stale they also answer at once with older results,
+marked "stale": true, which are checked again in the background so the
+next call gets fresh ones.
+
+Flags:
+`)
+		fs.PrintDefaults()
+	}
+	applyConfig(fs)
+	fs.Parse(args)
+
+	s := &rpcServer{
+		ctx:      ctx,
+		w:        os.Stdout,
+		watches:  make(map[int]context.CancelFunc),
+		cacheTTL: *cacheTTL,
+		maxStale: *maxStale,
+	}
+	s.reval = newRevalidator(ctx, func(ctx context.Context, domains []string, opts domainr.Options) error {
+		return streamDomains(ctx, domains, opts, func(domainr.DomainResult) error { return nil })
+	})
+	s.serve(os.Stdin)
+}
+
+// serve handles requests until r is exhausted, then waits for in-flight
+// checks and stops any watches.
+func (s *rpcServer) serve(r io.Reader) {
+	var wg sync.WaitGroup
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var req rpcRequest
+		if err := json.Unmarshal([]byte(line), &req); err != nil {
+			s.reply(nil, nil, &rpcError{rpcParseError, err.Error()})
+			continue
+		}
+		if req.JSONRPC != "2.0" || req.Method == "" {
+			s.reply(req.ID, nil, &rpcError{rpcInvalidRequest, "expected a JSON-RPC 2.0 request"})
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			result, err := s.handle(req)
+			if req.ID == nil {
+				return // notification, no response wanted
+			}
+			if err != nil {
+				var rerr *rpcError
+				if !errors.As(err, &rerr) {
+					rerr = &rpcError{rpcInternalError, err.Error()}
+				}
+				s.reply(req.ID, nil, rerr)
+				return
+			}
+			s.reply(req.ID, result, nil)
+		}()
+	}
+	wg.Wait()
+
+	s.mu.Lock()
+	for _, cancel := range s.watches {
+		cancel()
+	}
+	s.mu.Unlock()
+}
+
+func (s *rpcServer) handle(req rpcRequest) (any, error) {
+	switch req.Method {
+	case "check":
+		var p checkParams
+		domains, opts, err := s.parseCheck(req.Params, &p)
+		if err != nil {
+			return nil, err
+		}
+		results, err := checkDomains(s.ctx, domains, s.cached(opts))
+		if err != nil {
+			return nil, err
+		}
+		return results, nil
+
+	case "suggest":
+		var p struct {
+			Keywords []string `json:"keywords"`
+			TLDs     []string `json:"tlds"`
+			Max      int      `json:"max"`
+			Backend  string   `json:"backend"`
+		}
+		if err := json.Unmarshal(req.Params, &p); err != nil {
+			return nil, &rpcError{rpcInvalidParams, err.Error()}
+		}
+		if len(p.TLDs) == 0 {
+			p.TLDs = defaultTLDs
+		}
+		if p.Max == 0 {
+			p.Max = 60
+		}
+		domains, why := suggestDomains(p.Keywords, p.TLDs, p.Max)
+		if len(domains) == 0 {
+			return nil, &rpcError{rpcInvalidParams, "no candidates from the given keywords"}
+		}
+		opts := domainr.Options{Headless: true}
+		opts.Backend, opts.Fallbacks = splitBackends(p.Backend)
+		results, err := checkDomains(s.ctx, domains, s.cached(opts))
+		for i := range results {
+			results[i].Rationale = why[strings.ToLower(results[i].Domain)]
+		}
+		return results, err
+
+	case "watch":
+		var p checkParams
+		domains, opts, err := s.parseCheck(req.Params, &p)
+		if err != nil {
+			return nil, err
+		}
+		interval := 6 * time.Hour
+		if p.Interval != "" {
+			if interval, err = time.ParseDuration(p.Interval); err != nil || interval <= 0 {
+				return nil, &rpcError{rpcInvalidParams, "interval must be a positive duration like \"6h\""}
+			}
+		}
+		return map[string]int{"watch": s.startWatch(domains, opts, interval)}, nil
+
+	case "unwatch":
+		var p struct {
+			Watch int `json:"watch"`
+		}
+		if err := json.Unmarshal(req.Params, &p); err != nil {
+			return nil, &rpcError{rpcInvalidParams, err.Error()}
+		}
+		s.mu.Lock()
+		cancel, ok := s.watches[p.Watch]
+		delete(s.watches, p.Watch)
+		s.mu.Unlock()
+		if !ok {
+			return nil, &rpcError{rpcInvalidParams, fmt.Sprintf("no watch %d", p.Watch)}
+		}
+		cancel()
+		return true, nil
+
+	default:
+		return nil, &rpcError{rpcMethodNotFound, fmt.Sprintf("unknown method %q", req.Method)}
+	}
+}
+
+// parseCheck decodes check params into p and returns the validated domains
+// and options to check them with.
+func (s *rpcServer) parseCheck(raw json.RawMessage, p *checkParams) ([]string, domainr.Options, error) {
+	if err := json.Unmarshal(raw, p); err != nil {
+		return nil, domainr.Options{}, &rpcError{rpcInvalidParams, err.Error()}
+	}
+	tlds := p.TLDs
+	if len(tlds) == 0 {
+		tlds = defaultTLDs
+	}
+	domains := expandTLDs(p.Domains, tlds)
+	if len(domains) == 0 {
+		return nil, domainr.Options{}, &rpcError{rpcInvalidParams, "no domains given"}
+	}
+	for _, d := range domains {
+		if !validDomain(d) {
+			return nil, domainr.Options{}, &rpcError{rpcInvalidParams, fmt.Sprintf("invalid domain: %s", d)}
+		}
+	}
+	domains, _ = dedupeDomains(domains)
+	opts := domainr.Options{Headless: true}
+	opts.Backend, opts.Fallbacks = splitBackends(p.Backend)
+	return domains, opts, nil
+}
+
+// cached sets opts up to use the cache as configured by the rpc flags.
+func (s *rpcServer) cached(opts domainr.Options) domainr.Options {
+	opts.CacheTTL = s.cacheTTL
+	opts.CacheDir = dataDir()
+	opts.MaxStale = s.maxStale
+	return s.reval.serveStale(opts)
+}
+
+// startWatch runs a watch in the background, sending its output as
+// notifications tagged with the returned id.
+func (s *rpcServer) startWatch(domains []string, opts domainr.Options, interval time.Duration) int {
+	ctx, cancel := context.WithCancel(s.ctx)
+	s.mu.Lock()
+	s.nextID++
+	id := s.nextID
+	s.watches[id] = cancel
+	s.mu.Unlock()
+
+	go watchLoop(ctx, domains, opts, interval, watchHooks{
+		baseline: func(results []domainr.DomainResult) {
+			s.notify("watch.results", map[string]any{"watch": id, "results": results})
+		},
+		change: func(c statusChange) {
+			s.notify("watch.change", map[string]any{"watch": id, "change": c})
+		},
+		waiting: func(err error, next time.Time) {
+			params := map[string]any{"watch": id, "next_check": next}
+			if err != nil {
+				params["error"] = err.Error()
+			}
+			s.notify("watch.waiting", params)
+		},
+	})
+	return id
+}
+
+func (s *rpcServer) reply(id json.RawMessage, result any, rerr *rpcError) {
+	resp := rpcResponse{JSONRPC: "2.0", ID: id, Error: rerr}
+	if rerr == nil {
+		data, err := json.Marshal(result)
+		if err != nil {
+			resp.Error = &rpcError{rpcInternalError, err.Error()}
+		}
+		resp.Result = data
+	}
+	s.send(resp)
+}
+
+func (s *rpcServer) notify(method string, params any) {
+	s.send(rpcNotification{JSONRPC: "2.0", Method: method, Params: params})
+}
+
+func (s *rpcServer) send(m any) {
+	data, err := json.Marshal(m)
+	if err != nil {
+		return
+	}
+	s.wmu.Lock()
+	defer s.wmu.Unlock()
+	fmt.Fprintf(s.w, "%s\n", data)
+}
@@ -0,0 +1,269 @@
+package main
+
+import (
+	"context"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha512"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"flag"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// signatureAlgorithm is Ed25519 over the SHA-512 digest of the signed file
+// (Ed25519ph), so exports are signed as they stream out.
+const signatureAlgorithm = "ed25519ph"
+
+// signature is the detached signature -sign writes for a JSON export.
+type signature struct {
+	Algorithm string `json:"algorithm"`
+	PublicKey string `json:"public_key"`
+	Signature string `json:"signature"`
+}
+
+// keyPaths returns where the signing key pair is kept: next to the config
+// file, since unlike the data directory it is not a cache.
+func keyPaths() (private, public string, err error) {
+	config := configPath()
+	if config == "" {
+		return "", "", errors.New("no config directory to keep the signing key in")
+	}
+	dir := filepath.Dir(config)
+	return filepath.Join(dir, "signing.key"), filepath.Join(dir, "signing.pub"), nil
+}
+
+func runKeys(ctx context.Context, args []string) {
+	if len(args) > 0 && args[0] == "verify" {
+		runKeysVerify(args[1:])
+		return
+	}
+	fs := flag.NewFlagSet("keys", flag.ExitOnError)
+	force := fs.Bool("force", false, "Replace an existing key pair; signatures made with it can then only be checked with its saved public key")
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, `Usage: domainr keys [-force]
+       domainr keys verify [-key public-key] [-sig file] <report.json>
+
+Print the public key that -sign signs JSON exports with, generating an
+Ed25519 key pair next to the config file the first time. Share the public
+key with whoever needs to check your exports.
+
+keys verify checks a signed export against its signature (report.json.sig
+by default) and a trusted public key: -key, given as the key itself or a
+file containing it, or your own.
+
+Flags:
+`)
+		fs.PrintDefaults()
+	}
+	applyConfig(fs)
+	fs.Parse(args)
+
+	privPath, pubPath, err := keyPaths()
+	if err == nil && !*force {
+		var data []byte
+		if data, err = os.ReadFile(pubPath); err == nil {
+			fmt.Print(string(data))
+			return
+		}
+	}
+	if err == nil || errors.Is(err, os.ErrNotExist) {
+		err = generateKeys(privPath, pubPath)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	data, _ := os.ReadFile(pubPath)
+	fmt.Fprintf(os.Stderr, "Generated a signing key in %s\n", privPath)
+	fmt.Print(string(data))
+}
+
+// generateKeys writes a new key pair: the private key as PKCS #8 PEM,
+// readable only by the user, and the public key base64-encoded on one line.
+func generateKeys(privPath, pubPath string) error {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return err
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(priv)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(privPath), 0o700); err != nil {
+		return err
+	}
+	if err := os.WriteFile(privPath, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0o600); err != nil {
+		return err
+	}
+	return os.WriteFile(pubPath, []byte(base64.StdEncoding.EncodeToString(pub)+"\n"), 0o644)
+}
+
+// loadSigningKey reads the private key generated by domainr keys.
+func loadSigningKey() (ed25519.PrivateKey, error) {
+	privPath, _, err := keyPaths()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(privPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, errors.New("no signing key; run domainr keys to generate one")
+	}
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("%s: not a PEM private key", privPath)
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", privPath, err)
+	}
+	priv, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("%s: not an Ed25519 key", privPath)
+	}
+	return priv, nil
+}
+
+// parsePublicKey reads a base64 public key, or a file containing one.
+func parsePublicKey(s string) (ed25519.PublicKey, error) {
+	if data, err := os.ReadFile(s); err == nil {
+		s = string(data)
+	}
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(s))
+	if err != nil || len(raw) != ed25519.PublicKeySize {
+		return nil, errors.New("not an Ed25519 public key from domainr keys")
+	}
+	return ed25519.PublicKey(raw), nil
+}
+
+func runKeysVerify(args []string) {
+	fs := flag.NewFlagSet("keys verify", flag.ExitOnError)
+	key := fs.String("key", "", "Trusted public `key`, or a file containing it (default: your own, from domainr keys)")
+	sigPath := fs.String("sig", "", "Signature `file` (default: the export's path with .sig appended)")
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, `Usage: domainr keys verify [-key public-key] [-sig file] <report.json>
+
+Check that a JSON export is exactly as it was signed with -sign, by the
+holder of a trusted key.
+
+Flags:
+`)
+		fs.PrintDefaults()
+	}
+	applyConfig(fs)
+	files := parseArgs(fs, args)
+	if len(files) != 1 {
+		fs.Usage()
+		os.Exit(1)
+	}
+	if *sigPath == "" {
+		*sigPath = files[0] + ".sig"
+	}
+
+	trusted := *key
+	if trusted == "" {
+		_, pubPath, err := keyPaths()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		trusted = pubPath
+	}
+	if err := verifyExport(files[0], *sigPath, trusted); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s: %v\n", files[0], err)
+		os.Exit(1)
+	}
+	fmt.Printf("%s: good signature\n", files[0])
+}
+
+// verifyExport checks the export at path against its signature file and
+// the trusted public key.
+func verifyExport(path, sigPath, trusted string) error {
+	pub, err := parsePublicKey(trusted)
+	if err != nil {
+		return fmt.Errorf("trusted key: %w", err)
+	}
+	data, err := os.ReadFile(sigPath)
+	if err != nil {
+		return err
+	}
+	var sig signature
+	if err := json.Unmarshal(data, &sig); err != nil {
+		return fmt.Errorf("%s: %w", sigPath, err)
+	}
+	if sig.Algorithm != signatureAlgorithm {
+		return fmt.Errorf("%s: unsupported algorithm %q", sigPath, sig.Algorithm)
+	}
+	if sig.PublicKey != base64.StdEncoding.EncodeToString(pub) {
+		return fmt.Errorf("signed by %s, not the trusted key", sig.PublicKey)
+	}
+	raw, err := base64.StdEncoding.DecodeString(sig.Signature)
+	if err != nil {
+		return fmt.Errorf("%s: %w", sigPath, err)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	h := sha512.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return err
+	}
+	if err := ed25519.VerifyWithOptions(pub, h.Sum(nil), raw, &ed25519.Options{Hash: crypto.SHA512}); err != nil {
+		return errors.New("bad signature: the export was changed after signing")
+	}
+	return nil
+}
+
+// newSigningJSONSink returns a JSON sink whose output is signed into a
+// signature file at path once it closes.
+func newSigningJSONSink(w io.Writer, path string) (resultSink, error) {
+	key, err := loadSigningKey()
+	if err != nil {
+		return nil, err
+	}
+	h := sha512.New()
+	return &signingSink{resultSink: &jsonSink{w: io.MultiWriter(w, h)}, hash: h, key: key, path: path}, nil
+}
+
+// signingSink hashes the JSON its sink writes and, on Close, signs it into a
+// detached signature file.
+type signingSink struct {
+	resultSink
+	hash hash.Hash
+	key  ed25519.PrivateKey
+	path string
+}
+
+func (s *signingSink) Close() error {
+	if err := s.resultSink.Close(); err != nil {
+		return err
+	}
+	raw, err := s.key.Sign(nil, s.hash.Sum(nil), &ed25519.Options{Hash: crypto.SHA512})
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(signature{
+		Algorithm: signatureAlgorithm,
+		PublicKey: base64.StdEncoding.EncodeToString(s.key.Public().(ed25519.PublicKey)),
+		Signature: base64.StdEncoding.EncodeToString(raw),
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(s.path, append(data, '\n'), 0o644)
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/jpoz/domainr/pkg/domainr"
+)
+
+var webhookClient = &http.Client{Timeout: 15 * time.Second}
+
+// postSlack posts a message to a Slack incoming webhook. text is Slack
+// mrkdwn.
+func postSlack(url, text string) error {
+	payload, err := json.Marshal(map[string]string{"text": text})
+	if err != nil {
+		return err
+	}
+	resp, err := webhookClient.Post(url, "application/json", bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("slack: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		var body bytes.Buffer
+		body.ReadFrom(resp.Body)
+		return fmt.Errorf("slack: unexpected status %d: %s", resp.StatusCode, strings.TrimSpace(body.String()))
+	}
+	return nil
+}
+
+// notifySlack posts text, reporting failures on stderr without stopping the
+// run.
+func notifySlack(url, text string) {
+	if err := postSlack(url, text); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+	}
+}
+
+// slackEscape escapes the characters Slack treats as markup.
+var slackEscape = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// slackLine formats one result as a line of a Slack message.
+func slackLine(r domainr.DomainResult) string {
+	var icon, label string
+	switch r.Status {
+	case domainr.StatusAvailable:
+		icon, label = ":large_green_circle:", "Available"
+	case domainr.StatusPremium:
+		icon, label = ":large_purple_circle:", "Premium"
+	case domainr.StatusMakeOffer:
+		icon, label = ":large_purple_circle:", "Make offer"
+	case domainr.StatusTaken:
+		icon, label = ":red_circle:", "Taken"
+	case domainr.StatusReserved:
+		icon, label = ":red_circle:", "Reserved"
+	case domainr.StatusRestricted:
+		icon, label = ":large_yellow_circle:", "Restricted"
+	default:
+		icon, label = ":white_circle:", "Unknown"
+	}
+	line := fmt.Sprintf("%s *%s*  %s", icon, slackEscape.Replace(r.Domain), label)
+	if r.Price != "" && r.Status != domainr.StatusTaken {
+		line += "  " + slackEscape.Replace(r.Price)
+	}
+	if r.Status == domainr.StatusUnknown && r.Reason != "" {
+		line += "  _" + slackEscape.Replace(r.Reason) + "_"
+	}
+	return line
+}
+
+// slackSink collects the requested results and posts them to Slack as one
+// message on close.
+type slackSink struct {
+	resultSink
+	url     string
+	results []domainr.DomainResult
+}
+
+func (s *slackSink) Write(r domainr.DomainResult) error {
+	if !r.Related {
+		s.results = append(s.results, r)
+	}
+	return s.resultSink.Write(r)
+}
+
+func (s *slackSink) Close() error {
+	err := s.resultSink.Close()
+	if len(s.results) == 0 {
+		return err
+	}
+	available := 0
+	lines := make([]string, len(s.results))
+	for i, r := range s.results {
+		if isAvailable(r.Status) {
+			available++
+		}
+		lines[i] = slackLine(r)
+	}
+	notifySlack(s.url, fmt.Sprintf("*%d of %d domains available*\n%s", available, len(s.results), strings.Join(lines, "\n")))
+	return err
+}
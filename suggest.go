@@ -0,0 +1,232 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/jpoz/domainr/pkg/domainr"
+)
+
+// suggestPrefixes and suggestSuffixes are affixes commonly added to a name
+// when the bare name is taken.
+var (
+	suggestPrefixes = []string{"get", "try", "use", "my", "go", "hey", "the", "join"}
+	suggestSuffixes = []string{"app", "hq", "hub", "labs", "ly", "kit", "now", "ify"}
+)
+
+// hackTLDs are TLDs short and open enough to end a domain hack, where the
+// TLD spells the end of the word (delicio.us).
+var hackTLDs = []string{
+	"ai", "al", "am", "as", "at", "be", "by", "ch", "co", "de", "es", "fm", "im",
+	"in", "io", "is", "it", "la", "li", "ly", "me", "nu", "re", "se", "sh", "so",
+	"st", "to", "tv", "us", "ws",
+}
+
+// suggestSynonyms is a small offline thesaurus of words common in product
+// names.
+var suggestSynonyms = map[string][]string{
+	"build":   {"make", "craft", "forge"},
+	"cloud":   {"sky", "nimbus", "vapor"},
+	"code":    {"dev", "script", "source"},
+	"data":    {"info", "facts", "stats"},
+	"fast":    {"quick", "swift", "rapid", "zippy"},
+	"find":    {"seek", "scout", "spot"},
+	"fox":     {"vixen", "kit", "reynard"},
+	"garden":  {"grove", "yard", "bloom"},
+	"happy":   {"glad", "joy", "merry"},
+	"home":    {"nest", "haven", "abode"},
+	"idea":    {"spark", "notion", "muse"},
+	"light":   {"glow", "beam", "lumen", "ray"},
+	"list":    {"roster", "index", "ledger"},
+	"market":  {"bazaar", "mart", "exchange"},
+	"money":   {"cash", "coin", "fund"},
+	"note":    {"memo", "jot", "scribe"},
+	"path":    {"trail", "route", "way"},
+	"plan":    {"map", "chart", "scheme"},
+	"quick":   {"fast", "swift", "brisk"},
+	"shop":    {"store", "mart", "boutique"},
+	"small":   {"tiny", "mini", "micro"},
+	"smart":   {"clever", "bright", "savvy"},
+	"space":   {"orbit", "cosmos", "void"},
+	"star":    {"nova", "astro", "stellar"},
+	"team":    {"crew", "squad", "tribe"},
+	"time":    {"clock", "tempo", "hour"},
+	"track":   {"trace", "log", "follow"},
+	"travel":  {"voyage", "trek", "roam", "journey"},
+	"water":   {"aqua", "tide", "wave"},
+	"word":    {"lexi", "verb", "term"},
+	"work":    {"task", "labor", "craft"},
+	"writer":  {"scribe", "author", "quill"},
+	"zen":     {"calm", "still", "serene"},
+	"bright":  {"vivid", "radiant", "lucid"},
+	"connect": {"link", "bridge", "join"},
+}
+
+func runSuggest(ctx context.Context, args []string) {
+	fs := flag.NewFlagSet("suggest", flag.ExitOnError)
+	tlds := fs.String("tlds", strings.Join(defaultTLDs, ","), "Comma-separated TLDs to try generated names on")
+	limit := fs.Int("max", 60, "Maximum number of candidate domains to check")
+	backend := fs.String("backend", domainr.SourceNamecheap, "How to check domains: namecheap (browser scraping), namecheap-api, porkbun, godaddy, or rdap; a comma-separated list falls back to each next backend for domains the previous one couldn't check")
+	dryRun := fs.Bool("n", false, "Print the candidates without checking them")
+	visible := fs.Bool("visible", false, "Show the browser window (useful for debugging)")
+	debug := fs.Bool("debug", false, "Log scraping diagnostics to stderr")
+	jsonOut := fs.Bool("json", false, "Print results as JSON")
+	noColor := fs.Bool("no-color", false, "Disable colored output (also disabled by NO_COLOR or when stdout is not a terminal)")
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, `Usage: domainr suggest [flags] <keyword> [...]
+
+Generate candidate domains from keywords and check their availability.
+Candidates combine the keywords with each other, with synonyms from an
+offline thesaurus, and with common prefixes and suffixes (getfoo, foohq),
+most pronounceable first, plus domain hacks that spell the end of a keyword
+with its TLD (delicio.us). With -backend godaddy, GoDaddy's own suggestions
+for the keywords are added after them.
+
+Flags:
+`)
+		fs.PrintDefaults()
+	}
+	applyConfig(fs)
+	keywords := parseArgs(fs, args)
+	setColor(*noColor)
+	if len(keywords) == 0 {
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	opts := domainr.Options{Headless: !*visible, Debug: *debug, DNSPrecheck: true}
+	opts.Backend, opts.Fallbacks = splitBackends(*backend)
+
+	domains, why := suggestDomains(keywords, splitList(*tlds), *limit)
+	if slices.Contains(append([]string{opts.Backend}, opts.Fallbacks...), domainr.SourceGoDaddy) {
+		domains = addGoDaddySuggestions(ctx, domains, why, keywords, splitList(*tlds), *limit)
+	}
+	if len(domains) == 0 {
+		fmt.Fprintln(os.Stderr, "Error: no candidates to check")
+		os.Exit(1)
+	}
+	if *dryRun {
+		for _, d := range domains {
+			fmt.Printf("%s\t%s\n", d, why[d])
+		}
+		return
+	}
+	fmt.Fprintf(os.Stderr, "Checking %d candidate(s)\n", len(domains))
+
+	checkAndPrint(ctx, prepareDomains(domains), opts, outputConfig{JSON: *jsonOut, Rationale: why})
+}
+
+// addGoDaddySuggestions adds GoDaddy's suggestions for keywords to domains,
+// recording why in why. Suggestions, up to a quarter of limit, go after the
+// generated candidates, which are trimmed to make room.
+func addGoDaddySuggestions(ctx context.Context, domains []string, why map[string]string, keywords, tlds []string, limit int) []string {
+	want := 0
+	if limit > 0 {
+		want = max(limit/4, 1)
+	}
+	suggested, err := domainr.GoDaddySuggestions(ctx, strings.Join(keywords, " "), tlds, want, domainr.GoDaddyConfig{})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: no GoDaddy suggestions: %v\n", err)
+		return domains
+	}
+	var added []string
+	for _, d := range suggested {
+		d = strings.ToLower(d)
+		if _, seen := why[d]; !seen && validDomain(d) {
+			why[d] = "GoDaddy suggestion"
+			added = append(added, d)
+		}
+	}
+	if limit > 0 && len(domains)+len(added) > limit {
+		domains = domains[:max(limit-len(added), 0)]
+	}
+	return append(domains, added...)
+}
+
+// suggestDomains generates up to limit candidate domains from keywords,
+// along with why each was generated, keyed by domain. Bare names are tried on
+// each TLD; the keywords themselves come first.
+func suggestDomains(keywords, tlds []string, limit int) ([]string, map[string]string) {
+	var labels []string
+	for _, k := range keywords {
+		if l := toLabel(k); l != "" {
+			labels = append(labels, l)
+		}
+	}
+
+	// candidate is a generated label or, for hacks, a whole domain
+	type candidate struct{ name, why string }
+	var names, combos, hacks []candidate
+	for _, l := range labels {
+		names = append(names, candidate{l, "keyword"})
+	}
+	for _, a := range labels {
+		for _, b := range labels {
+			if a != b {
+				combos = append(combos, candidate{a + b, fmt.Sprintf("keywords %q + %q", a, b)})
+			}
+		}
+		for _, syn := range suggestSynonyms[a] {
+			names = append(names, candidate{syn, fmt.Sprintf("synonym of %q", a)})
+		}
+		for _, p := range suggestPrefixes {
+			combos = append(combos, candidate{p + a, fmt.Sprintf("prefix %q + keyword %q", p, a)})
+		}
+		for _, s := range suggestSuffixes {
+			combos = append(combos, candidate{a + s, fmt.Sprintf("keyword %q + suffix %q", a, s)})
+		}
+		for _, h := range domainHacks(a) {
+			hacks = append(hacks, candidate{h, fmt.Sprintf("domain hack spelling %q", a)})
+		}
+	}
+
+	// Put the most natural-sounding combinations first
+	sort.SliceStable(combos, func(i, j int) bool {
+		return pronounceability(combos[i].name) > pronounceability(combos[j].name)
+	})
+
+	var domains []string
+	why := make(map[string]string)
+	add := func(d, reason string) {
+		if _, seen := why[d]; !seen && validDomain(d) {
+			why[d] = reason
+			domains = append(domains, d)
+		}
+	}
+	for _, n := range names {
+		for _, d := range expandTLDs([]string{n.name}, tlds) {
+			add(d, n.why)
+		}
+	}
+	for _, h := range hacks {
+		add(h.name, h.why)
+	}
+	for _, c := range combos {
+		for _, d := range expandTLDs([]string{c.name}, tlds) {
+			add(d, c.why)
+		}
+	}
+
+	if limit > 0 && len(domains) > limit {
+		domains = domains[:limit]
+	}
+	return domains, why
+}
+
+// domainHacks returns domains that spell word by ending it with a TLD, e.g.
+// "delicious" -> "delicio.us". The remaining label must be at least two
+// characters.
+func domainHacks(word string) []string {
+	var hacks []string
+	for _, tld := range hackTLDs {
+		if stem, ok := strings.CutSuffix(word, tld); ok && len(stem) >= 2 && !strings.HasSuffix(stem, "-") {
+			hacks = append(hacks, stem+"."+tld)
+		}
+	}
+	return hacks
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/jpoz/domainr/pkg/domainr"
+)
+
+// telemetryURL is where -telemetry reports selector health. Telemetry is
+// off unless it is set.
+var telemetryURL string
+
+// setTelemetry opts in to telemetry sent to raw, exiting on an invalid URL.
+func setTelemetry(raw string) {
+	if raw == "" {
+		return
+	}
+	if u, err := url.Parse(raw); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		fmt.Fprintln(os.Stderr, "Error: -telemetry must be an http:// or https:// URL")
+		os.Exit(1)
+	}
+	telemetryURL = raw
+}
+
+// reportTelemetry posts the selector health counted since the last report,
+// if telemetry is on and anything was scraped. A failed report only warns:
+// telemetry never fails a run.
+func reportTelemetry() {
+	if telemetryURL == "" {
+		return
+	}
+	report := domainr.TakeSelectorReport()
+	if len(report.Selectors) == 0 && len(report.Builds) == 0 {
+		return
+	}
+	if err := postTelemetry(report); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: sending telemetry: %v\n", err)
+	}
+}
+
+func postTelemetry(report domainr.SelectorReport) error {
+	payload, err := json.Marshal(report)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, telemetryURL, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "domainr")
+	resp, err := webhookClient.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// reportTelemetryEvery reports selector health each interval until ctx is
+// done, for long-running processes like the daemon.
+func reportTelemetryEvery(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			reportTelemetry()
+		}
+	}
+}
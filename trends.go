@@ -0,0 +1,277 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jpoz/domainr/pkg/domainr"
+)
+
+// tldTrend summarizes the recorded checks of one TLD's domains.
+type tldTrend struct {
+	TLD        string    `json:"tld"`
+	Domains    int       `json:"domains"`
+	Checks     int       `json:"checks"`
+	FirstCheck time.Time `json:"first_checked"`
+	LastCheck  time.Time `json:"last_checked"`
+	AvgPrice   float64   `json:"avg_price,omitempty"`
+	// PriceChange is the average change, in percent, between the first and
+	// last price seen for each of PricedDomains domains priced at least
+	// twice.
+	PriceChange   float64      `json:"price_change_pct"`
+	PricedDomains int          `json:"priced_domains"`
+	Monthly       []monthPrice `json:"monthly_prices,omitempty"`
+	// PremiumShare is the fraction of registrable checks that were premium.
+	PremiumShare float64 `json:"premium_share"`
+	// Drops and Registrations count domains seen becoming available and
+	// becoming taken between consecutive checks; Churn is the fraction of
+	// re-checks that saw either.
+	Drops         int     `json:"drops"`
+	Registrations int     `json:"registrations"`
+	Churn         float64 `json:"churn"`
+}
+
+// monthPrice is the average price recorded in a month, e.g. "2025-01".
+type monthPrice struct {
+	Month    string  `json:"month"`
+	AvgPrice float64 `json:"avg_price"`
+}
+
+// trendCheck is one recorded check, as trends reads it.
+type trendCheck struct {
+	domain    string
+	status    domainr.DomainStatus
+	price     string
+	checkedAt time.Time
+}
+
+func runTrends(ctx context.Context, args []string) {
+	fs := flag.NewFlagSet("trends", flag.ExitOnError)
+	tlds := fs.String("tld", "", "Comma-separated TLDs to analyze (default: every TLD in the history)")
+	since := fs.Duration("since", 0, "Only use checks from this long ago onwards, e.g. 2160h for 90 days (default: all)")
+	jsonOut := fs.Bool("json", false, "Print the trends as JSON")
+	noColor := fs.Bool("no-color", false, "Disable colored output (also disabled by NO_COLOR or when stdout is not a terminal)")
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, `Usage: domainr trends [-tld ai,io] [flags]
+
+Analyze the local history database per TLD: the average price and how
+prices moved, month by month and per domain, how often registrable domains
+were premium, and availability churn, the domains seen dropping or being
+registered between checks. Prices are averaged as recorded, so mixing
+currencies in one TLD skews them.
+
+Flags:
+`)
+		fs.PrintDefaults()
+	}
+	applyConfig(fs)
+	fs.Parse(args)
+	setColor(*noColor)
+
+	db := openHistory()
+	if db == nil {
+		os.Exit(1)
+	}
+	var from time.Time
+	if *since > 0 {
+		from = time.Now().Add(-*since)
+	}
+	checks, err := loadTrendChecks(db, from)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	var want []string
+	for _, tld := range splitList(*tlds) {
+		want = append(want, strings.ToLower(strings.TrimPrefix(tld, ".")))
+	}
+	trends := computeTrends(checks, want)
+
+	if *jsonOut {
+		if trends == nil {
+			trends = []tldTrend{}
+		}
+		data, _ := json.MarshalIndent(trends, "", "  ")
+		fmt.Println(string(data))
+		return
+	}
+	if len(trends) == 0 {
+		fmt.Fprintln(os.Stderr, "No recorded checks to analyze; checks are recorded automatically unless -no-history is passed")
+		return
+	}
+	fmt.Println()
+	for _, t := range trends {
+		printTrend(t)
+	}
+}
+
+// loadTrendChecks reads the checks recorded since from, grouped by domain
+// and oldest first.
+func loadTrendChecks(db *sql.DB, from time.Time) ([]trendCheck, error) {
+	rows, err := db.Query(`SELECT domain, status, price, checked_at FROM checks WHERE checked_at >= ? ORDER BY domain, checked_at, id`, from.UTC())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var checks []trendCheck
+	for rows.Next() {
+		var c trendCheck
+		var status string
+		if err := rows.Scan(&c.domain, &status, &c.price, &c.checkedAt); err != nil {
+			return nil, err
+		}
+		c.status.UnmarshalText([]byte(status))
+		checks = append(checks, c)
+	}
+	return checks, rows.Err()
+}
+
+// computeTrends summarizes checks, grouped by domain and oldest first, per
+// TLD, restricted to the TLDs in want when given. Trends are sorted by TLD.
+func computeTrends(checks []trendCheck, want []string) []tldTrend {
+	type priceSum struct {
+		total float64
+		n     int
+	}
+	type acc struct {
+		trend       tldTrend
+		prices      priceSum
+		months      map[string]*priceSum
+		registrable int
+		premium     int
+		rechecks    int
+		changeSum   float64
+	}
+	byTLD := make(map[string]*acc)
+
+	for start := 0; start < len(checks); {
+		end := start + 1
+		for end < len(checks) && checks[end].domain == checks[start].domain {
+			end++
+		}
+		domainChecks := checks[start:end]
+		start = end
+
+		_, tld, _ := strings.Cut(domainChecks[0].domain, ".")
+		if len(want) > 0 && !slices.Contains(want, tld) {
+			continue
+		}
+		a, ok := byTLD[tld]
+		if !ok {
+			a = &acc{trend: tldTrend{TLD: tld}, months: make(map[string]*priceSum)}
+			byTLD[tld] = a
+		}
+		t := &a.trend
+		t.Domains++
+
+		var firstPrice, lastPrice float64
+		priced := 0
+		for i, c := range domainChecks {
+			t.Checks++
+			if t.FirstCheck.IsZero() || c.checkedAt.Before(t.FirstCheck) {
+				t.FirstCheck = c.checkedAt
+			}
+			if c.checkedAt.After(t.LastCheck) {
+				t.LastCheck = c.checkedAt
+			}
+			if isAvailable(c.status) {
+				a.registrable++
+				if c.status == domainr.StatusPremium {
+					a.premium++
+				}
+			}
+			if amount, ok := domainr.ParsePrice(c.price); ok && isAvailable(c.status) {
+				a.prices.total += amount
+				a.prices.n++
+				month := c.checkedAt.Format("2006-01")
+				if a.months[month] == nil {
+					a.months[month] = &priceSum{}
+				}
+				a.months[month].total += amount
+				a.months[month].n++
+				if priced == 0 {
+					firstPrice = amount
+				}
+				lastPrice = amount
+				priced++
+			}
+			if i > 0 {
+				a.rechecks++
+				was, is := isAvailable(domainChecks[i-1].status), isAvailable(c.status)
+				switch {
+				case !was && is:
+					t.Drops++
+				case was && !is:
+					t.Registrations++
+				}
+			}
+		}
+		if priced > 1 && firstPrice > 0 {
+			a.changeSum += (lastPrice - firstPrice) / firstPrice * 100
+			t.PricedDomains++
+		}
+	}
+
+	trends := make([]tldTrend, 0, len(byTLD))
+	for _, a := range byTLD {
+		t := a.trend
+		if a.prices.n > 0 {
+			t.AvgPrice = a.prices.total / float64(a.prices.n)
+		}
+		if t.PricedDomains > 0 {
+			t.PriceChange = a.changeSum / float64(t.PricedDomains)
+		}
+		for month, p := range a.months {
+			t.Monthly = append(t.Monthly, monthPrice{Month: month, AvgPrice: p.total / float64(p.n)})
+		}
+		slices.SortFunc(t.Monthly, func(x, y monthPrice) int { return strings.Compare(x.Month, y.Month) })
+		if a.registrable > 0 {
+			t.PremiumShare = float64(a.premium) / float64(a.registrable)
+		}
+		if a.rechecks > 0 {
+			t.Churn = float64(t.Drops+t.Registrations) / float64(a.rechecks)
+		}
+		trends = append(trends, t)
+	}
+	slices.SortFunc(trends, func(x, y tldTrend) int { return strings.Compare(x.TLD, y.TLD) })
+	return trends
+}
+
+func printTrend(t tldTrend) {
+	fmt.Printf("  %s.%s%s  %s%d checks of %d domains, %s – %s%s\n",
+		colorBold, t.TLD, colorReset, colorDim, t.Checks, t.Domains,
+		t.FirstCheck.Local().Format(time.DateOnly), t.LastCheck.Local().Format(time.DateOnly), colorReset)
+
+	if t.AvgPrice > 0 {
+		line := fmt.Sprintf("    price    average %.2f", t.AvgPrice)
+		if t.PricedDomains > 0 {
+			color := colorGreen
+			if t.PriceChange > 0 {
+				color = colorRed
+			}
+			line += fmt.Sprintf(", %s%+.1f%%%s per domain over %d re-priced", color, t.PriceChange, colorReset, t.PricedDomains)
+		}
+		fmt.Println(line)
+		if len(t.Monthly) > 1 {
+			months := make([]string, len(t.Monthly))
+			for i, m := range t.Monthly {
+				months[i] = fmt.Sprintf("%s %.2f", m.Month, m.AvgPrice)
+			}
+			fmt.Printf("             %s%s%s\n", colorDim, strings.Join(months, " → "), colorReset)
+		}
+	} else {
+		fmt.Printf("    price    %sno prices recorded%s\n", colorDim, colorReset)
+	}
+	fmt.Printf("    premium  %.0f%% of registrable checks\n", t.PremiumShare*100)
+	fmt.Printf("    churn    %s%d dropped%s, %s%d registered%s (%.0f%% of re-checks)\n",
+		colorGreen, t.Drops, colorReset, colorRed, t.Registrations, colorReset, t.Churn*100)
+	fmt.Println()
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jpoz/domainr/pkg/domainr"
+)
+
+func TestComputeTrends(t *testing.T) {
+	jan := time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC)
+	feb := time.Date(2025, 2, 10, 0, 0, 0, 0, time.UTC)
+	checks := []trendCheck{
+		{domain: "a.io", status: domainr.StatusAvailable, price: "$40.00", checkedAt: jan},
+		{domain: "a.io", status: domainr.StatusAvailable, price: "$50.00", checkedAt: feb},
+		{domain: "b.io", status: domainr.StatusTaken, checkedAt: jan},
+		{domain: "b.io", status: domainr.StatusPremium, price: "$90.00", checkedAt: feb},
+		{domain: "c.com", status: domainr.StatusAvailable, checkedAt: jan},
+		{domain: "c.com", status: domainr.StatusTaken, checkedAt: feb},
+	}
+
+	tests := []struct {
+		name string
+		want []string
+		// check is run on the trends computed
+		check func(t *testing.T, trends []tldTrend)
+	}{
+		{
+			name: "every tld",
+			check: func(t *testing.T, trends []tldTrend) {
+				if len(trends) != 2 || trends[0].TLD != "com" || trends[1].TLD != "io" {
+					t.Fatalf("trends = %+v, want com and io", trends)
+				}
+				com := trends[0]
+				if com.Registrations != 1 || com.Drops != 0 || com.Churn != 1 || com.AvgPrice != 0 {
+					t.Errorf("com = %+v", com)
+				}
+			},
+		},
+		{
+			name: "restricted to io",
+			want: []string{"io"},
+			check: func(t *testing.T, trends []tldTrend) {
+				if len(trends) != 1 {
+					t.Fatalf("trends = %+v, want io only", trends)
+				}
+				io := trends[0]
+				if io.Domains != 2 || io.Checks != 4 || !io.FirstCheck.Equal(jan) || !io.LastCheck.Equal(feb) {
+					t.Errorf("io counts = %+v", io)
+				}
+				if io.AvgPrice != 60 || io.PricedDomains != 1 || io.PriceChange != 25 {
+					t.Errorf("io prices = %.2f avg, %+.1f%% over %d", io.AvgPrice, io.PriceChange, io.PricedDomains)
+				}
+				if len(io.Monthly) != 2 || io.Monthly[0] != (monthPrice{"2025-01", 40}) || io.Monthly[1] != (monthPrice{"2025-02", 70}) {
+					t.Errorf("io monthly = %+v", io.Monthly)
+				}
+				if io.PremiumShare != 1.0/3 || io.Drops != 1 || io.Registrations != 0 || io.Churn != 0.5 {
+					t.Errorf("io availability = %+v", io)
+				}
+			},
+		},
+		{
+			name: "unrecorded tld",
+			want: []string{"ai"},
+			check: func(t *testing.T, trends []tldTrend) {
+				if len(trends) != 0 {
+					t.Errorf("trends = %+v, want none", trends)
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.check(t, computeTrends(checks, tt.want))
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/jpoz/domainr/pkg/domainr"
+)
+
+// statusChange is a domain becoming available between two watch rounds.
+type statusChange struct {
+	Domain    string               `json:"domain"`
+	From      domainr.DomainStatus `json:"from"`
+	To        domainr.DomainStatus `json:"to"`
+	Price     string               `json:"price,omitempty"`
+	ChangedAt time.Time            `json:"changed_at"`
+}
+
+// watchDomains re-checks domains every interval until ctx is cancelled,
+// reporting only domains that go from taken to available. The first round's
+// results are printed in full as a baseline, and changes are passed to alerts.
+func watchDomains(ctx context.Context, domains []string, opts domainr.Options, out outputConfig, interval time.Duration, alerts *alerter) {
+	go alerts.run(ctx)
+	// Slack hears about changes only, through the alerter
+	baseline := out
+	baseline.Slack = ""
+	watchLoop(ctx, domains, opts, interval, watchHooks{
+		baseline: func(results []domainr.DomainResult) { printResults(results, baseline) },
+		change: func(c statusChange) {
+			printChange(c, out)
+			alerts.change(c)
+		},
+		waiting: func(err error, next time.Time) {
+			alerts.roundDone()
+			reportTelemetry()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			}
+			fmt.Fprintf(os.Stderr, "Watching %d domain(s), next check at %s\n", len(domains), next.Format(time.DateTime))
+		},
+	})
+}
+
+// watchHooks receive a watch's output.
+type watchHooks struct {
+	// baseline gets the first round's results.
+	baseline func([]domainr.DomainResult)
+	// change gets each domain that became available.
+	change func(statusChange)
+	// waiting is called after every round with the round's error, if any,
+	// and when the next round starts.
+	waiting func(err error, next time.Time)
+}
+
+// watchLoop runs watch rounds every interval until ctx is cancelled.
+func watchLoop(ctx context.Context, domains []string, opts domainr.Options, interval time.Duration, hooks watchHooks) {
+	// Every round must really re-check, or changes would hide behind the cache
+	opts.CacheTTL = 0
+	last := make(map[string]domainr.DomainStatus)
+	for round := 0; ; round++ {
+		results, err := checkDomains(ctx, domains, opts)
+		if ctx.Err() != nil {
+			return
+		}
+
+		if round == 0 {
+			hooks.baseline(results)
+		}
+		for _, r := range results {
+			key := strings.ToLower(r.Domain)
+			// A failed check says nothing about whether the status changed
+			if r.Status == domainr.StatusUnknown {
+				continue
+			}
+			if last[key] == domainr.StatusTaken && isAvailable(r.Status) {
+				hooks.change(statusChange{
+					Domain:    r.Domain,
+					From:      last[key],
+					To:        r.Status,
+					Price:     r.Price,
+					ChangedAt: r.CheckedAt,
+				})
+			}
+			last[key] = r.Status
+		}
+
+		hooks.waiting(err, time.Now().Add(interval))
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+	}
+}
+
+// isAvailable reports whether a domain with status s can be registered.
+func isAvailable(s domainr.DomainStatus) bool {
+	return s == domainr.StatusAvailable || s == domainr.StatusPremium
+}
+
+func printChange(c statusChange, out outputConfig) {
+	if out.JSON {
+		data, _ := json.Marshal(c)
+		fmt.Println(string(data))
+		return
+	}
+	fmt.Printf("  %s  %s%s%s  %s → %s%s%s  %s%s%s\n",
+		c.ChangedAt.Format(time.DateTime),
+		colorBold, c.Domain, colorReset,
+		c.From,
+		colorGreen, c.To, colorReset,
+		colorDim, c.Price, colorReset)
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/jpoz/domainr/pkg/domainr"
+)
+
+// webhookEvent is the JSON body posted to -webhook: a run's full result set,
+// or one status change in watch mode.
+type webhookEvent struct {
+	// Event is "results" or "change"
+	Event   string                 `json:"event"`
+	Results []domainr.DomainResult `json:"results,omitempty"`
+	Change  *statusChange          `json:"change,omitempty"`
+}
+
+// postWebhook posts event as JSON to url, treating any 2xx response as
+// delivered.
+func postWebhook(url string, event webhookEvent) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("webhook: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "domainr")
+	resp, err := webhookClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		var body bytes.Buffer
+		body.ReadFrom(resp.Body)
+		return fmt.Errorf("webhook: unexpected status %d: %s", resp.StatusCode, strings.TrimSpace(body.String()))
+	}
+	return nil
+}
+
+// notifyWebhook posts event, reporting failures on stderr without stopping
+// the run.
+func notifyWebhook(url string, event webhookEvent) {
+	if err := postWebhook(url, event); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+	}
+}
+
+// webhookSink collects every result, related ones included, and posts them
+// as one "results" event on close.
+type webhookSink struct {
+	resultSink
+	url     string
+	results []domainr.DomainResult
+}
+
+func (s *webhookSink) Write(r domainr.DomainResult) error {
+	s.results = append(s.results, r)
+	return s.resultSink.Write(r)
+}
+
+func (s *webhookSink) Close() error {
+	err := s.resultSink.Close()
+	if len(s.results) > 0 {
+		notifyWebhook(s.url, webhookEvent{Event: "results", Results: s.results})
+	}
+	return err
+}